@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/task": {
+        "/admin/retention": {
             "get": {
-                "description": "Возвращает список задач с возможностью фильтрации по статусу",
+                "description": "Возвращает текущие правила хранения задач и количество удаленных по ним задач",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,20 +25,129 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "admin"
                 ],
-                "summary": "Получение списка задач",
+                "summary": "Получение правил хранения задач",
+                "responses": {
+                    "200": {
+                        "description": "Правила хранения получены",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Retention"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет правила хранения задач; новые правила применяются при следующей проверке",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение правил хранения задач",
+                "parameters": [
+                    {
+                        "description": "Правила хранения",
+                        "name": "retention",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRetentionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правила хранения изменены",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Retention"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/archive": {
+            "get": {
+                "description": "Возвращает архивные задачи, последнее изменение которых попадает в интервал, в порядке изменения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "archive"
+                ],
+                "summary": "Поиск архивных задач",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Статус для фильтрации (pending, processing, completed, failed, retrying)",
-                        "name": "status",
+                        "description": "Начало интервала (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец интервала (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество задач (1-1000, по умолчанию 100)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список задач получен",
+                        "description": "Архивные задачи получены",
                         "schema": {
                             "allOf": [
                                 {
@@ -50,7 +159,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/domain.Task"
+                                                "$ref": "#/definitions/domain.ArchivedTask"
                                             }
                                         }
                                     }
@@ -64,6 +173,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.Response"
                         }
                     },
+                    "404": {
+                        "description": "Архив отключен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -71,9 +186,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Создает новую задачу в системе",
+            }
+        },
+        "/archive/task/{id}": {
+            "get": {
+                "description": "Возвращает последнюю архивную копию задачи, удаленной из хранилища по правилам хранения",
                 "consumes": [
                     "application/json"
                 ],
@@ -81,23 +198,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "archive"
                 ],
-                "summary": "Создание новой задачи",
+                "summary": "Получение архивной задачи по ID",
                 "parameters": [
                     {
-                        "description": "Данные для создания задачи",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Архивная задача найдена",
                         "schema": {
-                            "$ref": "#/definitions/dto.TaskRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.ArchivedTask"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена в архиве или архив отключен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
                         }
                     }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "Возвращает записи аудита по инициатору и интервалу времени в порядке выполнения команд",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Поиск в журнале аудита",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Инициатор изменения (заголовок X-Actor; system — изменения самого сервиса)",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало интервала (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец интервала (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество записей (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Задача успешно создана",
+                        "description": "Записи аудита получены",
                         "schema": {
                             "allOf": [
                                 {
@@ -107,7 +301,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.Task"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.AuditEntry"
+                                            }
                                         }
                                     }
                                 }
@@ -115,7 +312,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные запроса",
+                        "description": "Некорректные параметры запроса",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -129,9 +326,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/task/{id}": {
+        "/dlq/{queue}": {
             "get": {
-                "description": "Возвращает задачу по указанному идентификатору",
+                "description": "Возвращает страницу задач очереди, перенесенных в DLQ после окончательной ошибки выполнения.\nСледующая страница запрашивается с cursor из поля nextCursor ответа и тем же sort.",
                 "consumes": [
                     "application/json"
                 ],
@@ -139,21 +336,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "dlq"
                 ],
-                "summary": "Получение задачи по ID",
+                "summary": "Получение DLQ очереди",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID задачи",
-                        "name": "id",
+                        "description": "Имя очереди",
+                        "name": "queue",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Задача найдена",
+                        "description": "Задачи DLQ получены",
                         "schema": {
                             "allOf": [
                                 {
@@ -163,7 +378,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.Task"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Task"
+                                            }
                                         }
                                     }
                                 }
@@ -171,7 +389,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Некорректный ID задачи",
+                        "description": "Некорректные параметры запроса",
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
@@ -184,8 +402,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Обновляет статус задачи по указанному идентификатору",
+            "delete": {
+                "description": "Безвозвратно удаляет задачи из DLQ очереди, минуя архив. Без ids удаляются все задачи DLQ очереди; задачи, которых нет в DLQ очереди, пропускаются",
                 "consumes": [
                     "application/json"
                 ],
@@ -193,30 +411,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "dlq"
                 ],
-                "summary": "Обновление статуса задачи",
+                "summary": "Очистка DLQ",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID задачи",
-                        "name": "id",
+                        "description": "Имя очереди",
+                        "name": "queue",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Данные для обновления статуса",
-                        "name": "task",
+                        "description": "ID задач",
+                        "name": "tasks",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateTaskStatusRequest"
+                            "$ref": "#/definitions/dto.DeadLettersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Статус задачи обновлен",
+                        "description": "ID удаленных задач",
                         "schema": {
                             "allOf": [
                                 {
@@ -226,7 +443,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.Task"
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
                                         }
                                     }
                                 }
@@ -244,19 +464,1855 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.Response"
                         }
-                    }
+                    }
+                }
+            }
+        },
+        "/dlq/{queue}/requeue": {
+            "post": {
+                "description": "Переводит задачи из DLQ очереди в pending со сброшенным retryCount. Без ids в DLQ возвращаются все задачи очереди; задачи, которых нет в DLQ очереди, пропускаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dlq"
+                ],
+                "summary": "Массовый повтор задач из DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя очереди",
+                        "name": "queue",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID задач",
+                        "name": "tasks",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeadLettersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID возвращенных в очередь задач",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/dlq/{queue}/task/{id}": {
+            "get": {
+                "description": "Возвращает задачу из DLQ очереди с контекстом ошибки: deadLetter, lastError, retryCount и история попыток",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dlq"
+                ],
+                "summary": "Получение задачи из DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя очереди",
+                        "name": "queue",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача получена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задачи нет в DLQ очереди",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Безвозвратно удаляет задачу из DLQ очереди, минуя архив",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dlq"
+                ],
+                "summary": "Удаление задачи из DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя очереди",
+                        "name": "queue",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача удалена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задачи нет в DLQ очереди",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/dlq/{queue}/task/{id}/requeue": {
+            "post": {
+                "description": "Переводит задачу из DLQ очереди в pending со сброшенным retryCount. lastError и история попыток сохраняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dlq"
+                ],
+                "summary": "Повтор задачи из DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя очереди",
+                        "name": "queue",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача возвращена в очередь",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задачи нет в DLQ очереди",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{name}/claim": {
+            "post": {
+                "description": "Атомарно выбирает самую приоритетную ожидающую задачу очереди, переводит ее в processing и закрепляет за воркером",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Получение задачи из очереди",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя очереди",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные воркера",
+                        "name": "claim",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClaimTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача выдана воркеру",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "В очереди нет доступных задач",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule": {
+            "get": {
+                "description": "Возвращает все расписания с временем последнего и следующего срабатывания",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Получение списка расписаний",
+                "responses": {
+                    "200": {
+                        "description": "Список расписаний получен",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Schedule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает расписание, по которому задачи из шаблона создаются согласно cron-выражению",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Создание расписания",
+                "parameters": [
+                    {
+                        "description": "Данные расписания",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание создано",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Schedule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/{id}": {
+            "delete": {
+                "description": "Удаляет расписание; уже созданные им задачи не затрагиваются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Удаление расписания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание удалено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID расписания",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Расписание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task": {
+            "get": {
+                "description": "Возвращает страницу списка задач с фильтрацией и сортировкой. Все заданные фильтры объединяются по И.\nПри равенстве поля сортировки задачи упорядочиваются по createdAt, затем по ID.\nСледующая страница запрашивается с cursor из поля nextCursor ответа и тем же sort.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение списка задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус для фильтрации (blocked, scheduled, pending, processing, completed, failed, retrying, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Очередь",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип задачи",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Приоритет (low, medium, high, critical)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID воркера, выполняющего задачу",
+                        "name": "workerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID родительской задачи",
+                        "name": "parentId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не раньше (RFC3339)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не позже (RFC3339)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменена не раньше (RFC3339)",
+                        "name": "updatedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Изменена не позже (RFC3339)",
+                        "name": "updatedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальное количество попыток",
+                        "name": "minRetries",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество попыток",
+                        "name": "maxRetries",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Условие на метаданные ключ:значение; можно указать несколько",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список задач получен",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Task"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новую задачу в системе и возвращает ее ID. Повторный запрос с тем же ключом\nидемпотентности (заголовок Idempotency-Key или поле dedupKey) в течение IDEMPOTENCY_TTL\nне создает новую задачу, а возвращает ID исходной с флагом replayed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Создание новой задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Данные для создания задачи",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача создана или возвращена ранее созданная",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если возвращена ранее созданная задача"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса или зависимости",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/search": {
+            "get": {
+                "description": "Возвращает страницу задач, удовлетворяющих выражению, например\nqueue = \"billing\" AND priority IN (\"high\",\"critical\") AND metadata.user_id = \"123\" AND retryCount \u003e 2.\nОператоры: =, !=, \u003c, \u003c=, \u003e, \u003e=, IN, NOT IN; выражения объединяются AND, OR, NOT и скобками.\nОшибка разбора возвращается с позицией символа, на котором она обнаружена.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Поиск задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Выражение поиска",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1-1000, по умолчанию 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные задачи",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Task"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное выражение или параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}": {
+            "get": {
+                "description": "Возвращает задачу по указанному идентификатору",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение задачи по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача найдена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обновляет статус задачи по указанному идентификатору. Если передан заголовок If-Match\nс ETag задачи, обновление выполняется, только пока версия задачи не изменилась",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Обновление статуса задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag задачи, полученный при чтении",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Данные для обновления статуса",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTaskStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статус задачи обновлен",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи после обновления"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Недопустимый переход статуса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия задачи не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/attempts": {
+            "get": {
+                "description": "Возвращает завершенные попытки выполнения задачи от первой к последней: воркер, время начала и завершения, итог, ошибку и длительность. Хранятся только последние 20 попыток",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение истории попыток задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История попыток получена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.TaskAttempt"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/audit": {
+            "get": {
+                "description": "Возвращает все команды, выполненные над задачей, в порядке выполнения: инициатор, команда, статус до и после, ID запроса. Журнал доступен и после удаления задачи",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Получение журнала аудита задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал аудита получен",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.AuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/cancel": {
+            "post": {
+                "description": "Невыполняющаяся задача сразу переходит в cancelled. У выполняющейся задачи выставляется\ncancelRequested: воркер узнает об отмене из ответа на heartbeat и переводит задачу в cancelled.\nС cascade=true так же отменяются дочерние и зависящие от нее задачи, рекурсивно",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Отмена задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры отмены",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача отменена или отмена запрошена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача уже завершена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/children": {
+            "get": {
+                "description": "Возвращает прямых потомков задачи в порядке создания",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение дочерних задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Дочерние задачи получены",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.Task"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/complete": {
+            "post": {
+                "description": "Переводит задачу в completed и сохраняет output. Отчет принимается только от воркера, удерживающего аренду задачи",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Отчет об успешном выполнении задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отчет воркера",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CompleteTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача выполнена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача не удерживается воркером",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/fail": {
+            "post": {
+                "description": "Сохраняет ошибку в lastError и отправляет задачу на повтор по политике повторов, если попытки не исчерпаны и ошибка не окончательная (final), иначе переводит в failed. Отчет принимается только от воркера, удерживающего аренду задачи",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Отчет об ошибке выполнения задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отчет воркера",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FailTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ошибка зафиксирована, задача на повторе или завершена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача не удерживается воркером",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/heartbeat": {
+            "post": {
+                "description": "Продлевает аренду задачи, если она находится в processing и удерживается указанным воркером.\nЕсли для задачи запрошена отмена, в ответе cancelRequested=true: воркер должен прекратить выполнение и перевести задачу в cancelled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Продление аренды задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные воркера",
+                        "name": "heartbeat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.HeartbeatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аренда продлена",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.Task"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия задачи для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача не удерживается воркером",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/task/{id}/tree": {
+            "get": {
+                "description": "Возвращает задачу со всеми потомками в виде дерева",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение дерева задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Дерево задач получено",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.TaskTree"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.ArchivedTask": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "description": "Время архивации задачи\nexample: \"2024-01-15T10:00:00Z\"",
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/domain.Task"
+                }
+            }
+        },
+        "domain.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Инициатор изменения. Для запросов API — значение заголовка X-Actor, которое\nсервис не проверяет (см. actorClaimed)\nexample: \"billing-service\"",
+                    "type": "string"
+                },
+                "actorClaimed": {
+                    "description": "Инициатор заявлен клиентом в заголовке X-Actor и не подтвержден аутентификацией\nexample: true",
+                    "type": "boolean"
+                },
+                "causedBy": {
+                    "description": "ID задачи, изменение которой повлекло изменение этой задачи\n(например, отмена или ошибка зависимости); пусто для основной задачи команды\nexample: \"task-456\"",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Имя команды\nexample: \"UpdateTask\"",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Ошибка, с которой завершилась команда\nexample: \"invalid status transition\"",
+                    "type": "string"
+                },
+                "requestId": {
+                    "description": "ID HTTP-запроса, в рамках которого выполнена команда\nexample: \"7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b\"",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Порядковый номер записи в журнале\nexample: 42",
+                    "type": "integer"
+                },
+                "statusAfter": {
+                    "description": "Статус задачи после выполнения команды\nexample: \"completed\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskStatus"
+                        }
+                    ]
+                },
+                "statusBefore": {
+                    "description": "Статус задачи до выполнения команды\nexample: \"processing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskStatus"
+                        }
+                    ]
+                },
+                "taskId": {
+                    "description": "ID задачи, над которой выполнена команда\nexample: \"550e8400-e29b-41d4-a716-446655440000\"",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Время выполнения команды\nexample: \"2024-01-15T10:00:00Z\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "description": "Время переноса в DLQ\nexample: \"2024-01-15T10:05:00Z\"",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Причина переноса\nenum: RETRIES_EXHAUSTED,FINAL_ERROR\nexample: \"RETRIES_EXHAUSTED\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EvictionStats": {
+            "type": "object",
+            "properties": {
+                "byQueue": {
+                    "description": "Удалено задач по очередям",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "byStatus": {
+                    "description": "Удалено задач по статусам",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "lastRunAt": {
+                    "description": "Время последней проверки",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Всего удалено задач\nexample: 42",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.MisfirePolicy": {
+            "type": "string",
+            "enum": [
+                "fire_once",
+                "skip"
+            ],
+            "x-enum-varnames": [
+                "MisfirePolicyFireOnce",
+                "MisfirePolicySkip"
+            ]
+        },
+        "domain.Retention": {
+            "type": "object",
+            "properties": {
+                "deadLetterTtl": {
+                    "description": "Время хранения задач в DLQ с момента переноса или never; правила policy к ним не применяются\nexample: \"720h0m0s\"",
+                    "type": "string"
+                },
+                "evictions": {
+                    "$ref": "#/definitions/domain.EvictionStats"
+                },
+                "policy": {
+                    "$ref": "#/definitions/domain.RetentionPolicy"
+                }
+            }
+        },
+        "domain.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RetentionRule"
+                    }
+                }
+            }
+        },
+        "domain.RetentionRule": {
+            "type": "object",
+            "properties": {
+                "queue": {
+                    "description": "Очередь задач, к которым применяется правило\nexample: \"reports\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус задач, к которым применяется правило\nexample: \"completed\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskStatus"
+                        }
+                    ]
+                },
+                "ttl": {
+                    "description": "Время хранения после последнего изменения задачи или never\nexample: \"1h\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RetryPolicy": {
+            "type": "object",
+            "properties": {
+                "delayMs": {
+                    "description": "Базовая задержка в миллисекундах\nexample: 1000",
+                    "type": "integer"
+                },
+                "jitter": {
+                    "description": "Доля случайного уменьшения задержки от 0 до 1\nexample: 0.2",
+                    "type": "number"
+                },
+                "maxDelayMs": {
+                    "description": "Максимальная задержка в миллисекундах (0 — не более суток)\nexample: 60000",
+                    "type": "integer"
+                },
+                "strategy": {
+                    "description": "Стратегия расчета задержки\nenum: fixed,linear,exponential\nexample: \"exponential\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RetryStrategy"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.RetryStrategy": {
+            "type": "string",
+            "enum": [
+                "fixed",
+                "linear",
+                "exponential"
+            ],
+            "x-enum-varnames": [
+                "RetryStrategyFixed",
+                "RetryStrategyLinear",
+                "RetryStrategyExponential"
+            ]
+        },
+        "domain.Schedule": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Время создания расписания\nexample: \"2024-01-15T09:00:00Z\"",
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron-выражение из пяти полей или дескриптор (@daily, @hourly, ...)\nexample: \"0 3 * * *\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор расписания\nexample: \"schedule-123\"",
+                    "type": "string"
+                },
+                "lastError": {
+                    "description": "Ошибка последнего срабатывания, при котором не удалось создать задачу\nexample: \"dependency task-456 not found\"",
+                    "type": "string"
+                },
+                "lastRunAt": {
+                    "description": "Время последнего срабатывания\nexample: \"2024-01-15T03:00:00Z\"",
+                    "type": "string"
+                },
+                "lastTaskId": {
+                    "description": "ID задачи, созданной последним срабатыванием\nexample: \"task-123\"",
+                    "type": "string"
+                },
+                "misfirePolicy": {
+                    "description": "Поведение при пропущенном срабатывании\nenum: fire_once,skip\nexample: \"fire_once\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MisfirePolicy"
+                        }
+                    ]
+                },
+                "missedRuns": {
+                    "description": "Количество пропущенных срабатываний\nexample: 0",
+                    "type": "integer"
+                },
+                "nextRunAt": {
+                    "description": "Время следующего срабатывания\nexample: \"2024-01-16T03:00:00Z\"",
+                    "type": "string"
+                },
+                "template": {
+                    "description": "Шаблон создаваемой задачи",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskTemplate"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA, в котором вычисляется расписание\nexample: \"Europe/Moscow\"",
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "domain.Task": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "description": "История завершенных попыток выполнения, не более MaxTaskAttempts последних",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TaskAttempt"
+                    }
+                },
+                "cancelRequested": {
+                    "description": "Запрошена отмена выполняющейся задачи; воркер узнает об этом из ответа на heartbeat\nи должен прекратить выполнение, переведя задачу в cancelled\nexample: false",
+                    "type": "boolean"
+                },
+                "children": {
+                    "description": "Сводное состояние дочерних задач",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskAggregate"
+                        }
+                    ]
+                },
                 "createdAt": {
                     "description": "Время создания задачи\nexample: \"2024-01-15T09:00:00Z\"",
                     "type": "string"
                 },
+                "deadLetter": {
+                    "description": "Отметка о переносе в DLQ очереди после окончательной ошибки выполнения",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DeadLetter"
+                        }
+                    ]
+                },
                 "dependsOn": {
                     "description": "Список зависимостей\nexample: [\"task-456\", \"task-789\"]",
                     "type": "array",
@@ -280,6 +2336,10 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "leaseExpiresAt": {
+                    "description": "Время истечения аренды задачи воркером\nexample: \"2024-01-15T10:00:30Z\"",
+                    "type": "string"
+                },
                 "maxRetries": {
                     "description": "Максимальное количество попыток\nexample: 3",
                     "type": "integer"
@@ -317,6 +2377,14 @@ const docTemplate = `{
                     "description": "Количество попыток выполнения\nexample: 0",
                     "type": "integer"
                 },
+                "retryPolicy": {
+                    "description": "Политика задержки между повторами",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RetryPolicy"
+                        }
+                    ]
+                },
                 "scheduledAt": {
                     "description": "Время планируемого выполнения\nexample: \"2024-01-15T10:00:00Z\"",
                     "type": "string"
@@ -326,7 +2394,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "status": {
-                    "description": "Текущий статус задачи\nenum: pending,processing,completed,failed,retrying\nexample: \"pending\"",
+                    "description": "Текущий статус задачи\nenum: blocked,scheduled,pending,processing,completed,failed,retrying,cancelled\nexample: \"pending\"",
                     "allOf": [
                         {
                             "$ref": "#/definitions/domain.TaskStatus"
@@ -341,12 +2409,94 @@ const docTemplate = `{
                     "description": "Время последнего обновления\nexample: \"2024-01-15T09:00:00Z\"",
                     "type": "string"
                 },
+                "version": {
+                    "description": "Версия задачи; увеличивается хранилищем при каждой записи\nexample: 3",
+                    "type": "integer"
+                },
                 "workerId": {
                     "description": "ID воркера, выполняющего задачу\nexample: \"worker-1\"",
                     "type": "string"
                 }
             }
         },
+        "domain.TaskAggregate": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "description": "Отменены\nexample: 0",
+                    "type": "integer"
+                },
+                "completed": {
+                    "description": "Выполнены успешно\nexample: 1",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Завершены с ошибкой\nexample: 0",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Ожидают выполнения (blocked, scheduled, pending)\nexample: 1",
+                    "type": "integer"
+                },
+                "running": {
+                    "description": "Выполняются (processing, retrying)\nexample: 1",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Сводный статус: failed, если хотя бы одна дочерняя задача failed;\ncancelled, если все cancelled; completed, если все завершены и хотя бы одна completed;\nprocessing, если выполнение началось; иначе pending\nexample: \"processing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskStatus"
+                        }
+                    ]
+                },
+                "total": {
+                    "description": "Количество дочерних задач\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TaskAttempt": {
+            "type": "object",
+            "properties": {
+                "durationMs": {
+                    "description": "Длительность попытки в миллисекундах\nexample: 5000",
+                    "type": "integer"
+                },
+                "errorCode": {
+                    "description": "Код ошибки попытки\nexample: \"LEASE_EXPIRED\"",
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "description": "Сообщение об ошибке попытки\nexample: \"worker lease expired\"",
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "description": "Время завершения попытки\nexample: \"2024-01-15T10:00:05Z\"",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Порядковый номер попытки, считая с 1\nexample: 2",
+                    "type": "integer"
+                },
+                "outcome": {
+                    "description": "Статус, в который задача перешла по итогам попытки\nenum: completed,failed,retrying,cancelled\nexample: \"retrying\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskStatus"
+                        }
+                    ]
+                },
+                "startedAt": {
+                    "description": "Время начала попытки\nexample: \"2024-01-15T10:00:00Z\"",
+                    "type": "string"
+                },
+                "workerId": {
+                    "description": "ID воркера, выполнявшего попытку\nexample: \"worker-1\"",
+                    "type": "string"
+                }
+            }
+        },
         "domain.TaskError": {
             "type": "object",
             "properties": {
@@ -382,20 +2532,199 @@ const docTemplate = `{
         "domain.TaskStatus": {
             "type": "string",
             "enum": [
+                "blocked",
+                "scheduled",
                 "pending",
                 "processing",
                 "completed",
                 "failed",
-                "retrying"
+                "retrying",
+                "cancelled"
             ],
             "x-enum-varnames": [
+                "TaskStatusBlocked",
+                "TaskStatusScheduled",
                 "TaskStatusPending",
                 "TaskStatusProcessing",
                 "TaskStatusCompleted",
                 "TaskStatusFailed",
-                "TaskStatusRetrying"
+                "TaskStatusRetrying",
+                "TaskStatusCancelled"
             ]
         },
+        "domain.TaskTemplate": {
+            "type": "object",
+            "properties": {
+                "dependsOn": {
+                    "description": "Список зависимостей\nexample: [\"task-456\", \"task-789\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxRetries": {
+                    "description": "Максимальное количество попыток\nexample: 3",
+                    "type": "integer"
+                },
+                "metadata": {
+                    "description": "Метаданные задачи\nexample: {\"source\": \"api\", \"user_id\": \"123\"}",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "parentTaskId": {
+                    "description": "ID родительской задачи\nexample: \"task-123\"",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Полезная нагрузка задачи\nexample: {\"email\": \"user@example.com\", \"subject\": \"Test\"}",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "priority": {
+                    "description": "Приоритет задачи\nenum: low,medium,high,critical\nexample: \"high\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TaskPriority"
+                        }
+                    ]
+                },
+                "queue": {
+                    "description": "Очередь для выполнения\nexample: \"default\"",
+                    "type": "string"
+                },
+                "retryPolicy": {
+                    "description": "Политика задержки между повторами",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RetryPolicy"
+                        }
+                    ]
+                },
+                "type": {
+                    "description": "Тип задачи\nexample: \"email_send\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TaskTree": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TaskTree"
+                    }
+                },
+                "task": {
+                    "$ref": "#/definitions/domain.Task"
+                }
+            }
+        },
+        "dto.CancelTaskRequest": {
+            "type": "object",
+            "properties": {
+                "cascade": {
+                    "description": "Отменить также дочерние задачи и задачи, зависящие от отменяемой, рекурсивно\nexample: true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ClaimTaskRequest": {
+            "type": "object",
+            "properties": {
+                "leaseSeconds": {
+                    "description": "Длительность аренды в секундах (по умолчанию LEASE_TTL)\nminimum: 0\nexample: 30",
+                    "type": "integer"
+                },
+                "workerId": {
+                    "description": "ID воркера, забирающего задачу\nrequired: true\nexample: \"worker-1\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CompleteTaskRequest": {
+            "type": "object",
+            "properties": {
+                "output": {
+                    "description": "Результат выполнения задачи\nexample: {\"messageId\": \"abc-123\"}"
+                },
+                "workerId": {
+                    "description": "ID воркера, удерживающего задачу\nrequired: true\nexample: \"worker-1\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "description": "Cron-выражение из пяти полей или дескриптор (@daily, @hourly, ...)\nrequired: true\nexample: \"0 3 * * *\"",
+                    "type": "string"
+                },
+                "misfirePolicy": {
+                    "description": "Поведение при пропущенном срабатывании (по умолчанию fire_once)\nenum: fire_once,skip\nexample: \"fire_once\"",
+                    "type": "string"
+                },
+                "task": {
+                    "description": "Шаблон создаваемой задачи\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaskRequest"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Часовой пояс IANA (по умолчанию UTC)\nexample: \"Europe/Moscow\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeadLettersRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "ID задач; если не заданы, обрабатываются все задачи DLQ очереди\nexample: [\"550e8400-e29b-41d4-a716-446655440000\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.FailTaskRequest": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Ошибка выполнения\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TaskErrorRequest"
+                        }
+                    ]
+                },
+                "final": {
+                    "description": "Ошибка окончательная: повтор не поможет, задача сразу переходит в failed\nexample: false",
+                    "type": "boolean"
+                },
+                "workerId": {
+                    "description": "ID воркера, удерживающего задачу\nrequired: true\nexample: \"worker-1\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HeartbeatRequest": {
+            "type": "object",
+            "properties": {
+                "leaseSeconds": {
+                    "description": "Новая длительность аренды в секундах от текущего момента (по умолчанию LEASE_TTL)\nminimum: 0\nexample: 30",
+                    "type": "integer"
+                },
+                "workerId": {
+                    "description": "ID воркера, удерживающего задачу\nrequired: true\nexample: \"worker-1\"",
+                    "type": "string"
+                }
+            }
+        },
         "dto.Response": {
             "type": "object",
             "properties": {
@@ -406,15 +2735,65 @@ const docTemplate = `{
                     "description": "Сообщение об ошибке (если есть)\nexample: \"invalid request\"",
                     "type": "string"
                 },
+                "nextCursor": {
+                    "description": "Курсор следующей страницы; отсутствует, если страница последняя",
+                    "type": "string"
+                },
+                "replayed": {
+                    "description": "Запрос повторен с уже использованным ключом идемпотентности, и возвращен результат исходного запроса",
+                    "type": "boolean"
+                },
                 "status": {
                     "description": "HTTP-статус код\nexample: 200",
                     "type": "integer"
                 }
             }
         },
+        "dto.RetryPolicy": {
+            "type": "object",
+            "properties": {
+                "delayMs": {
+                    "description": "Базовая задержка в миллисекундах\nminimum: 0\nmaximum: 86400000\nexample: 1000",
+                    "type": "integer"
+                },
+                "jitter": {
+                    "description": "Доля случайного уменьшения задержки от 0 до 1\nexample: 0.2",
+                    "type": "number"
+                },
+                "maxDelayMs": {
+                    "description": "Максимальная задержка в миллисекундах (0 — не более суток)\nminimum: 0\nmaximum: 86400000\nexample: 60000",
+                    "type": "integer"
+                },
+                "strategy": {
+                    "description": "Стратегия расчета задержки\nrequired: true\nenum: fixed,linear,exponential\nexample: \"exponential\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TaskErrorRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Код ошибки\nexample: \"EMAIL_SEND_FAILED\"",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Сообщение об ошибке\nrequired: true\nexample: \"Failed to send email\"",
+                    "type": "string"
+                },
+                "stack": {
+                    "description": "Стек вызовов\nexample: \"main.main()\\n\\tmain.go:25 +0x123\"",
+                    "type": "string"
+                }
+            }
+        },
         "dto.TaskRequest": {
             "type": "object",
             "properties": {
+                "dedupKey": {
+                    "description": "Ключ идемпотентности: повторное создание с тем же ключом в течение IDEMPOTENCY_TTL\nвозвращает ID уже созданной задачи. Альтернатива заголовку Idempotency-Key\nexample: \"order-42-email\"",
+                    "type": "string"
+                },
                 "dependsOn": {
                     "description": "Список зависимостей\nexample: [\"task-456\", \"task-789\"]",
                     "type": "array",
@@ -452,6 +2831,14 @@ const docTemplate = `{
                     "description": "Количество попыток выполнения\nminimum: 0\nexample: 0",
                     "type": "integer"
                 },
+                "retryPolicy": {
+                    "description": "Политика задержки между повторами",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.RetryPolicy"
+                        }
+                    ]
+                },
                 "scheduledAt": {
                     "description": "Время планируемого выполнения\nexample: \"2024-01-15T10:00:00Z\"",
                     "type": "string"
@@ -462,6 +2849,18 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateRetentionRequest": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "description": "Правила хранения; к задаче применяется самое точное подходящее правило\n(очередь и статус, затем только очередь, затем только статус),\nзадачи без подходящего правила не удаляются\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RetentionRule"
+                    }
+                }
+            }
+        },
         "dto.UpdateTaskStatusRequest": {
             "type": "object",
             "properties": {
@@ -469,7 +2868,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "status": {
-                    "description": "Новый статус задачи\nrequired: true\nenum: pending,processing,completed,failed,retrying\nexample: \"completed\"",
+                    "description": "Новый статус задачи\nrequired: true\nenum: pending,completed,failed,cancelled\nexample: \"completed\"",
                     "type": "string"
                 }
             }
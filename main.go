@@ -9,8 +9,11 @@ import (
 	"os/signal"
 	"svc-task_master/src/common/config"
 	"svc-task_master/src/common/logger"
+	"svc-task_master/src/ports_adapters/primary/background"
 	"svc-task_master/src/ports_adapters/primary/http_server"
 	"svc-task_master/src/ports_adapters/secondary/inmemory/db"
+	ondisk "svc-task_master/src/ports_adapters/secondary/ondisk/db"
+	"svc-task_master/src/ports_adapters/secondary/repository"
 	"svc-task_master/src/ports_adapters/secondary/service/application"
 	"syscall"
 	"time"
@@ -33,10 +36,15 @@ func main() {
 	asyncLogeer.Info("Loaded configuration", slog.Any("config", cfg))
 
 	asyncLogeer.Info("Initializing repository...")
-	repo := db.NewRepository(asyncLogeer, cfg.MemoryDB.NumShards, cfg.MemoryDB.TTL)
+	repo, err := newRepository(asyncLogeer, cfg)
+	if err != nil {
+		asyncLogeer.Error("Failed to initialize repository", slog.String("error", err.Error()))
+		asyncLogeer.Shutdown()
+		os.Exit(1)
+	}
 
 	asyncLogeer.Info("Initializing application service...")
-	app := application.InitApp(repo.InMemoryDB, asyncLogeer)
+	app := application.InitApp(repo, asyncLogeer, cfg)
 
 	asyncLogeer.Info("Initializing HTTP server...")
 	s := http_server.NewServer(&app)
@@ -46,14 +54,47 @@ func main() {
 	r.POST("/task", s.CreateTask)
 	r.GET("/task/:id", s.GetTaskForId)
 	r.GET("/task", s.GetTasksSortStatus)
+	r.GET("/task/search", s.SearchTasks)
+	r.POST("/queue/:name/claim", s.ClaimTask)
+	r.POST("/task/:id/heartbeat", s.HeartbeatTask)
+	r.POST("/task/:id/cancel", s.CancelTask)
+	r.POST("/task/:id/complete", s.CompleteTask)
+	r.POST("/task/:id/fail", s.FailTask)
+	r.GET("/task/:id/children", s.GetTaskChildren)
+	r.GET("/task/:id/tree", s.GetTaskTree)
+	r.GET("/task/:id/attempts", s.GetTaskAttempts)
+	r.GET("/task/:id/audit", s.GetTaskAudit)
+	r.GET("/audit", s.GetAudit)
+	r.GET("/dlq/:queue", s.GetDeadLetters)
+	r.DELETE("/dlq/:queue", s.PurgeDeadLetters)
+	r.POST("/dlq/:queue/requeue", s.RequeueDeadLetters)
+	r.GET("/dlq/:queue/task/:id", s.GetDeadLetter)
+	r.DELETE("/dlq/:queue/task/:id", s.PurgeDeadLetter)
+	r.POST("/dlq/:queue/task/:id/requeue", s.RequeueDeadLetter)
+	r.POST("/schedule", s.CreateSchedule)
+	r.GET("/schedule", s.GetSchedules)
+	r.DELETE("/schedule/:id", s.DeleteSchedule)
+	r.GET("/admin/retention", s.GetRetention)
+	r.GET("/archive/task/:id", s.GetArchivedTask)
+	r.GET("/archive", s.GetArchivedTasks)
+	r.PUT("/admin/retention", s.UpdateRetention)
 	r.Handle("GET", "/swagger/*", httpSwagger.WrapHandler)
 
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
+
+	asyncLogeer.Info("Starting lease reaper...")
+	go background.NewLeaseReaper(&app, asyncLogeer, cfg.Worker.LeaseCheckInterval).Run(bgCtx)
+
+	asyncLogeer.Info("Starting schedule runner...")
+	go background.NewScheduleRunner(&app, asyncLogeer, cfg.Schedule.CheckInterval).Run(bgCtx)
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
-		Handler: r,
+		Handler: http_server.WithAudit(r),
 	}
 
 	go func() {
@@ -70,6 +111,8 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	bgCancel()
+
 	asyncLogeer.Info("Shutting down server...")
 	if err := server.Shutdown(ctx); err != nil {
 		asyncLogeer.Error("Server shutdown failed", slog.String("error", err.Error()))
@@ -77,8 +120,24 @@ func main() {
 		asyncLogeer.Info("Server shutdown completed successfully")
 	}
 
+	asyncLogeer.Info("Closing repository...")
+	if err := repo.Close(); err != nil {
+		asyncLogeer.Error("Repository close failed", slog.String("error", err.Error()))
+	}
+
 	asyncLogeer.Info("Shutting down logger...")
 	asyncLogeer.Info("Application exited properly")
 	asyncLogeer.Shutdown()
 
 }
+
+func newRepository(asyncLogeer *logger.CustomAsyncLogger, cfg *config.Config) (*repository.Repository, error) {
+	switch cfg.Storage.Driver {
+	case "memory":
+		return db.NewRepository(asyncLogeer, cfg)
+	case "disk":
+		return ondisk.NewRepository(asyncLogeer, cfg)
+	default:
+		return nil, fmt.Errorf("invalid storage driver: %s, must be one of: memory, disk", cfg.Storage.Driver)
+	}
+}
@@ -11,11 +11,37 @@ type App struct {
 }
 
 type Commands struct {
-	CreateTask commands.CreateTaskCommnad
-	UpdateTask commands.UpdateTaskCommnad
+	CreateTask           commands.CreateTaskCommnad
+	UpdateTask           commands.UpdateTaskCommnad
+	ClaimTask            commands.ClaimTaskCommnad
+	HeartbeatTask        commands.HeartbeatTaskCommnad
+	ReleaseExpiredLeases commands.ReleaseExpiredLeasesCommnad
+	CreateSchedule       commands.CreateScheduleCommnad
+	DeleteSchedule       commands.DeleteScheduleCommnad
+	FireSchedules        commands.FireSchedulesCommnad
+	UpdateRetention      commands.UpdateRetentionCommnad
+	CancelTask           commands.CancelTaskCommnad
+	CompleteTask         commands.CompleteTaskCommnad
+	FailTask             commands.FailTaskCommnad
+	RequeueDeadLetter    commands.RequeueDeadLetterCommnad
+	RequeueDeadLetters   commands.RequeueDeadLettersCommnad
+	PurgeDeadLetter      commands.PurgeDeadLetterCommnad
+	PurgeDeadLetters     commands.PurgeDeadLettersCommnad
 }
 
 type Queries struct {
-	GetTask  queries.GetTaskIdQuery
-	GetTasks queries.GetTasksQuery
+	GetTask          queries.GetTaskIdQuery
+	GetTasks         queries.GetTasksQuery
+	SearchTasks      queries.SearchTasksQuery
+	GetSchedules     queries.GetSchedulesQuery
+	GetTaskChildren  queries.GetTaskChildrenQuery
+	GetTaskTree      queries.GetTaskTreeQuery
+	GetTaskAttempts  queries.GetTaskAttemptsQuery
+	GetRetention     queries.GetRetentionQuery
+	GetArchivedTask  queries.GetArchivedTaskQuery
+	GetArchivedTasks queries.GetArchivedTasksQuery
+	GetTaskAudit     queries.GetTaskAuditQuery
+	GetAudit         queries.GetAuditQuery
+	GetDeadLetters   queries.GetDeadLettersQuery
+	GetDeadLetter    queries.GetDeadLetterQuery
 }
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type cancelTaskCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type CancelTaskCommnad decorator.CommandHandlerDecorator[dto.CancelTaskRequest, domain.Task]
+
+func NewCancelTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.CancelTaskRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.CancelTaskRequest, domain.Task](
+		cancelTaskCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+func (c cancelTaskCommnad) Handle(ctx context.Context, request dto.CancelTaskRequest) (domain.Task, error) {
+	task, err := c.cancel(request.Id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	cancelled := []domain.Task{task}
+	if request.Cascade {
+		cancelled = append(cancelled, c.cascade(ctx, task)...)
+	}
+	// Последствия применяются после каскада, чтобы зависимые задачи были отменены, а не завершены с ошибкой
+	for _, t := range cancelled {
+		if t.Status == domain.TaskStatusCancelled {
+			c.lifecycle.afterTransition(ctx, t)
+		}
+	}
+	return task, nil
+}
+
+func (c cancelTaskCommnad) cancel(id string) (domain.Task, error) {
+	return c.repo.Update(id, cancelTask)
+}
+
+func cancelTask(task *domain.Task) error {
+	_, err := task.Cancel(time.Now())
+	return err
+}
+
+// cascade отменяет потомков и зависимые задачи root в ширину; уже завершенные
+// задачи пропускаются, а их потомки и зависимые задачи не обходятся
+func (c cancelTaskCommnad) cascade(ctx context.Context, root domain.Task) []domain.Task {
+	var cancelled []domain.Task
+	visited := map[string]bool{root.ID: true}
+	queue := []string{root.ID}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, related := range c.related(ctx, id) {
+			if visited[related.ID] {
+				continue
+			}
+			visited[related.ID] = true
+
+			task, err := updateRelated(ctx, c.repo, related.ID, id, cancelTask)
+			if err != nil {
+				if !errors.Is(err, domain.ErrInvalidStatusTransition) && !errors.Is(err, domain.ErrTaskNotFound) {
+					c.logger.Error("Failed to cancel related task",
+						slog.String("task_id", related.ID),
+						slog.String("error", err.Error()),
+					)
+				}
+				continue
+			}
+			c.logger.Info("Task cancelled by cascade",
+				slog.String("task_id", task.ID),
+				slog.String("root_id", root.ID),
+				slog.String("status", string(task.Status)),
+			)
+			cancelled = append(cancelled, task)
+			queue = append(queue, task.ID)
+		}
+	}
+	return cancelled
+}
+
+func (c cancelTaskCommnad) related(ctx context.Context, id string) []domain.Task {
+	children, err := c.repo.GetChildren(ctx, id)
+	if err != nil {
+		c.logger.Error("Failed to get child tasks",
+			slog.String("task_id", id),
+			slog.String("error", err.Error()),
+		)
+	}
+	dependents, err := c.repo.GetDependents(ctx, id)
+	if err != nil {
+		c.logger.Error("Failed to get dependent tasks",
+			slog.String("task_id", id),
+			slog.String("error", err.Error()),
+		)
+	}
+	return append(children, dependents...)
+}
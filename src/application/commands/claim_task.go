@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type claimTaskCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+	leaseTTL  time.Duration
+}
+
+type ClaimTaskCommnad decorator.CommandHandlerDecorator[dto.ClaimTaskRequest, domain.Task]
+
+func NewClaimTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle, leaseTTL time.Duration) decorator.CommandHandlerDecorator[dto.ClaimTaskRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.ClaimTaskRequest, domain.Task](
+		claimTaskCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+			leaseTTL:  leaseTTL,
+		},
+		logger,
+	)
+
+}
+
+func (c claimTaskCommnad) Handle(ctx context.Context, request dto.ClaimTaskRequest) (domain.Task, error) {
+	lease := c.leaseTTL
+	if request.LeaseSeconds > 0 {
+		lease = time.Duration(request.LeaseSeconds) * time.Second
+	}
+	task, err := c.repo.ClaimNext(ctx, request.Queue, request.WorkerID, lease)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	c.lifecycle.afterTransition(ctx, task)
+	return task, nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type completeTaskCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type CompleteTaskCommnad decorator.CommandHandlerDecorator[dto.CompleteTaskRequest, domain.Task]
+
+func NewCompleteTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.CompleteTaskRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.CompleteTaskRequest, domain.Task](
+		completeTaskCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+func (c completeTaskCommnad) Handle(ctx context.Context, request dto.CompleteTaskRequest) (domain.Task, error) {
+	task, err := c.repo.Update(request.Id, func(task *domain.Task) error {
+		return task.Complete(request.WorkerID, request.Output, time.Now())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	c.lifecycle.afterTransition(ctx, task)
+	return task, nil
+}
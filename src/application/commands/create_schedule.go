@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type createScheduleCommnad struct {
+	logger domain.ILogger
+	repo   domain.IScheduleRepository
+}
+
+type CreateScheduleCommnad decorator.CommandHandlerDecorator[dto.CreateScheduleRequest, domain.Schedule]
+
+func NewCreateScheduleCommnad(logger domain.ILogger, repo domain.IScheduleRepository) decorator.CommandHandlerDecorator[dto.CreateScheduleRequest, domain.Schedule] {
+	return decorator.ApplyCommandLoggerDecorator[dto.CreateScheduleRequest, domain.Schedule](
+		createScheduleCommnad{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c createScheduleCommnad) Handle(ctx context.Context, request dto.CreateScheduleRequest) (domain.Schedule, error) {
+	now := time.Now()
+	schedule := domain.Schedule{
+		ID:            uuid.New().String(),
+		Cron:          request.Cron,
+		Timezone:      request.Timezone,
+		MisfirePolicy: domain.MisfirePolicy(request.MisfirePolicy),
+		Template:      createTaskTemplate(request.Task),
+		CreatedAt:     now,
+	}
+
+	next, err := nextScheduleRun(schedule, now)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+	if next.IsZero() {
+		return domain.Schedule{}, errors.New("cron expression never fires")
+	}
+	schedule.NextRunAt = next
+
+	if err := c.repo.Create(schedule); err != nil {
+		return domain.Schedule{}, err
+	}
+	return schedule, nil
+}
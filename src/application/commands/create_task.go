@@ -5,28 +5,62 @@ import (
 	"svc-task_master/src/common/decorator"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
 )
 
 type createTaskCommnad struct {
-	logger domain.ILogger
-	repo   domain.IInMemoRepository
+	logger      domain.ILogger
+	repo        domain.IInMemoRepository
+	idempotency domain.IIdempotencyRepository
+	lifecycle   TaskLifecycle
 }
 
-type CreateTaskCommnad decorator.CommandHandlerDecorator[dto.TaskRequest, string]
+type CreateTaskCommnad decorator.CommandHandlerDecorator[dto.TaskRequest, domain.CreatedTask]
 
-func NewCreateTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.TaskRequest, string] {
-	return decorator.ApplyCommandLoggerDecorator[dto.TaskRequest, string](
+func NewCreateTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle, idempotency domain.IIdempotencyRepository) decorator.CommandHandlerDecorator[dto.TaskRequest, domain.CreatedTask] {
+	return decorator.ApplyCommandLoggerDecorator[dto.TaskRequest, domain.CreatedTask](
 		createTaskCommnad{
-			logger: logger,
-			repo:   repo,
+			logger:      logger,
+			repo:        repo,
+			idempotency: idempotency,
+			lifecycle:   lifecycle,
 		},
 		logger,
 	)
 
 }
 
-func (c createTaskCommnad) Handle(ctx context.Context, request dto.TaskRequest) (string, error) {
+func (c createTaskCommnad) Handle(ctx context.Context, request dto.TaskRequest) (domain.CreatedTask, error) {
 	task := createTask(request)
-	c.repo.SetUpdate(task.ID, task)
-	return task.ID, nil
+
+	// Ключ закрепляется до создания задачи, чтобы одновременные повторы не создали дубликат
+	key := request.Key()
+	if key != "" {
+		originalID, replayed, err := c.idempotency.Reserve(key, task.ID, time.Now())
+		if err != nil {
+			return domain.CreatedTask{}, err
+		}
+		if replayed {
+			return domain.CreatedTask{ID: originalID, Replayed: true}, nil
+		}
+	}
+
+	if err := c.create(ctx, &task); err != nil {
+		if key != "" {
+			c.idempotency.Release(key, task.ID)
+		}
+		return domain.CreatedTask{}, err
+	}
+	return domain.CreatedTask{ID: task.ID}, nil
+}
+
+func (c createTaskCommnad) create(ctx context.Context, task *domain.Task) error {
+	if err := c.lifecycle.resolveDependencies(ctx, task); err != nil {
+		return err
+	}
+	if err := c.repo.SetUpdate(task.ID, *task); err != nil {
+		return err
+	}
+	c.lifecycle.afterTransition(ctx, *task)
+	return nil
 }
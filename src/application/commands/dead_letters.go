@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/domain"
+)
+
+// deadLetterIDs возвращает ID задач из запроса или, если они не заданы, всех задач DLQ очереди
+func deadLetterIDs(ctx context.Context, repo domain.IInMemoRepository, queue string, ids []string) ([]string, error) {
+	if len(ids) > 0 {
+		return ids, nil
+	}
+	page, err := repo.ListTasks(ctx, domain.TaskQuery{
+		Filter: domain.TaskFilter{Queue: queue, Status: domain.TaskStatusFailed},
+		Predicate: func(task *domain.Task) bool {
+			return task.InDeadLetterQueue(queue)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids = make([]string, 0, len(page.Tasks))
+	for _, task := range page.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids, nil
+}
+
+// inDeadLetterQueue разрешает удаление только задач из DLQ очереди queue
+func inDeadLetterQueue(queue string) func(task *domain.Task) error {
+	return func(task *domain.Task) error {
+		if !task.InDeadLetterQueue(queue) {
+			return domain.ErrDeadLetterNotFound
+		}
+		return nil
+	}
+}
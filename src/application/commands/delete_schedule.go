@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type deleteScheduleCommnad struct {
+	logger domain.ILogger
+	repo   domain.IScheduleRepository
+}
+
+type DeleteScheduleCommnad decorator.CommandHandlerDecorator[dto.DeleteScheduleRequest, any]
+
+func NewDeleteScheduleCommnad(logger domain.ILogger, repo domain.IScheduleRepository) decorator.CommandHandlerDecorator[dto.DeleteScheduleRequest, any] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeleteScheduleRequest, any](
+		deleteScheduleCommnad{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c deleteScheduleCommnad) Handle(ctx context.Context, request dto.DeleteScheduleRequest) (any, error) {
+	return nil, c.repo.Delete(request.ID)
+}
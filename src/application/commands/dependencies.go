@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"svc-task_master/src/domain"
+)
+
+// resolveDependencies проверяет родителя и зависимости новой задачи и блокирует ее,
+// пока не выполнены все зависимости. Связанные задачи, уже удаленные по правилам
+// хранения, ищутся в архиве. Задача может зависеть только от уже сохраненных задач,
+// а зависимости после создания не меняются, поэтому циклов не бывает
+func (l TaskLifecycle) resolveDependencies(ctx context.Context, task *domain.Task) error {
+	if task.ParentTaskID != "" {
+		if _, ok := l.find(ctx, task.ParentTaskID); !ok {
+			return fmt.Errorf("%w: %s", domain.ErrParentTaskNotFound, task.ParentTaskID)
+		}
+	}
+
+	blocked := false
+	for _, id := range task.DependsOn {
+		dep, ok := l.find(ctx, id)
+		if !ok {
+			return fmt.Errorf("%w: %s", domain.ErrDependencyNotFound, id)
+		}
+		switch dep.Status {
+		case domain.TaskStatusCompleted:
+		case domain.TaskStatusFailed, domain.TaskStatusCancelled:
+			return fmt.Errorf("%w: %s", domain.ErrDependencyFailed, id)
+		default:
+			blocked = true
+		}
+	}
+
+	if blocked {
+		task.Status = domain.TaskStatusBlocked
+	}
+	return nil
+}
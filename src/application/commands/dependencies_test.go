@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"svc-task_master/src/ports_adapters/secondary/archive"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"svc-task_master/src/ports_adapters/secondary/inmemory/db/idempotency_repo"
+	"svc-task_master/src/ports_adapters/secondary/inmemory/db/task_repo"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"testing"
+	"time"
+)
+
+// memoryArchive архив, задачи которого заданы тестом
+type memoryArchive map[string]domain.Task
+
+func (a memoryArchive) Archive(tasks []domain.Task, at time.Time) error {
+	for _, task := range tasks {
+		a[task.ID] = task
+	}
+	return nil
+}
+
+func (a memoryArchive) Get(ctx context.Context, id string) (domain.ArchivedTask, error) {
+	task, ok := a[id]
+	if !ok {
+		return domain.ArchivedTask{}, domain.ErrArchivedTaskNotFound
+	}
+	return domain.ArchivedTask{Task: task}, nil
+}
+
+func (a memoryArchive) Find(ctx context.Context, from, to time.Time, limit int) ([]domain.ArchivedTask, error) {
+	return nil, nil
+}
+
+func newCreateTask(t *testing.T, taskArchive domain.ITaskArchive) (CreateTaskCommnad, *task_repo.SharderStorage) {
+	t.Helper()
+	logger := contract.NopLogger{}
+	repo := task_repo.NewSharderStorage(4, retention.NewKeeper(domain.RetentionPolicy{}, 0, archive.Nop{}), 0, 0, logger)
+	idempotency := idempotency_repo.NewIdempotencyStorage(time.Hour, logger)
+	t.Cleanup(func() {
+		repo.Close()
+		idempotency.Close()
+	})
+	lifecycle := NewTaskLifecycle(logger, repo, taskArchive)
+	return NewCreateTaskCommnad(logger, repo, lifecycle, idempotency), repo
+}
+
+func taskRequest(dependsOn ...string) dto.TaskRequest {
+	return dto.TaskRequest{Type: "email", Queue: "default", Priority: string(domain.TaskPriorityMedium), DependsOn: dependsOn}
+}
+
+// TestDependenciesCannotFormCycle проверяет, что задача может зависеть только от задач,
+// сохраненных до нее: ни от себя, ни от задачи, созданной позже, поэтому граф зависимостей ацикличен
+func TestDependenciesCannotFormCycle(t *testing.T) {
+	create, repo := newCreateTask(t, archive.Nop{})
+	ctx := context.Background()
+
+	var created []string
+	for i := 0; i < 5; i++ {
+		result, err := create.Handle(ctx, taskRequest(created...))
+		if err != nil {
+			t.Fatalf("create task %d depending on all earlier tasks: %v", i, err)
+		}
+		created = append(created, result.ID)
+	}
+
+	// ID новой задачи известен до сохранения, но ссылка на него не разрешается
+	self := createTask(taskRequest())
+	self.DependsOn = []string{self.ID}
+	lifecycle := NewTaskLifecycle(contract.NopLogger{}, repo, archive.Nop{})
+	if err := lifecycle.resolveDependencies(ctx, &self); !errors.Is(err, domain.ErrDependencyNotFound) {
+		t.Fatalf("task depending on itself: %v, want ErrDependencyNotFound", err)
+	}
+
+	later := createTask(taskRequest())
+	if _, err := create.Handle(ctx, taskRequest(created[0], later.ID)); !errors.Is(err, domain.ErrDependencyNotFound) {
+		t.Fatalf("task depending on a task that is not created yet: %v, want ErrDependencyNotFound", err)
+	}
+
+	position := make(map[string]int, len(created))
+	for i, id := range created {
+		position[id] = i
+	}
+	for i, id := range created {
+		task, ok := repo.Get(id)
+		if !ok {
+			t.Fatalf("task %s not found", id)
+		}
+		for _, dep := range task.DependsOn {
+			if position[dep] >= i {
+				t.Fatalf("task %d depends on task %d created no earlier than itself", i, position[dep])
+			}
+		}
+	}
+}
+
+func TestCreateTaskResolvesArchivedDependencyAndParent(t *testing.T) {
+	taskArchive := memoryArchive{
+		"done":      {ID: "done", Status: domain.TaskStatusCompleted},
+		"failed":    {ID: "failed", Status: domain.TaskStatusFailed},
+		"parent":    {ID: "parent", Status: domain.TaskStatusCompleted},
+		"cancelled": {ID: "cancelled", Status: domain.TaskStatusCancelled},
+	}
+	create, repo := newCreateTask(t, taskArchive)
+	ctx := context.Background()
+
+	request := taskRequest("done")
+	request.ParentTaskID = "parent"
+	result, err := create.Handle(ctx, request)
+	if err != nil {
+		t.Fatalf("create task depending on an archived completed task: %v", err)
+	}
+	if task, _ := repo.Get(result.ID); task.Status != domain.TaskStatusPending {
+		t.Fatalf("task with archived completed dependency has status %s, want pending", task.Status)
+	}
+
+	for _, id := range []string{"failed", "cancelled"} {
+		if _, err := create.Handle(ctx, taskRequest(id)); !errors.Is(err, domain.ErrDependencyFailed) {
+			t.Fatalf("create task depending on archived %s task: %v, want ErrDependencyFailed", id, err)
+		}
+	}
+
+	request = taskRequest()
+	request.ParentTaskID = "missing"
+	if _, err := create.Handle(ctx, request); !errors.Is(err, domain.ErrParentTaskNotFound) {
+		t.Fatalf("create task with a missing parent: %v, want ErrParentTaskNotFound", err)
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type failTaskCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type FailTaskCommnad decorator.CommandHandlerDecorator[dto.FailTaskRequest, domain.Task]
+
+func NewFailTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.FailTaskRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.FailTaskRequest, domain.Task](
+		failTaskCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+func (c failTaskCommnad) Handle(ctx context.Context, request dto.FailTaskRequest) (domain.Task, error) {
+	taskErr := &domain.TaskError{
+		Message: request.Error.Message,
+		Stack:   request.Error.Stack,
+		Code:    request.Error.Code,
+	}
+	task, err := c.repo.Update(request.Id, func(task *domain.Task) error {
+		return task.FailAttempt(request.WorkerID, taskErr, request.Final, time.Now())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	c.lifecycle.afterTransition(ctx, task)
+	return task, nil
+}
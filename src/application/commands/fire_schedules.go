@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type fireSchedulesCommnad struct {
+	logger           domain.ILogger
+	repo             domain.IScheduleRepository
+	createTask       CreateTaskCommnad
+	misfireThreshold time.Duration
+}
+
+type FireSchedulesCommnad decorator.CommandHandlerDecorator[dto.FireSchedulesRequest, int]
+
+func NewFireSchedulesCommnad(
+	logger domain.ILogger,
+	repo domain.IScheduleRepository,
+	createTask CreateTaskCommnad,
+	misfireThreshold time.Duration,
+) decorator.CommandHandlerDecorator[dto.FireSchedulesRequest, int] {
+	return decorator.ApplyCommandLoggerDecorator[dto.FireSchedulesRequest, int](
+		fireSchedulesCommnad{
+			logger:           logger,
+			repo:             repo,
+			createTask:       createTask,
+			misfireThreshold: misfireThreshold,
+		},
+		logger,
+	)
+
+}
+
+func (c fireSchedulesCommnad) Handle(ctx context.Context, request dto.FireSchedulesRequest) (int, error) {
+	schedules, err := c.repo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, schedule := range schedules {
+		if !schedule.IsDue(request.Now) {
+			continue
+		}
+		ok, err := c.fire(ctx, schedule, request.Now)
+		if err != nil {
+			c.logger.Error("Failed to fire schedule",
+				slog.String("schedule_id", schedule.ID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+	return fired, nil
+}
+
+func (c fireSchedulesCommnad) fire(ctx context.Context, schedule domain.Schedule, now time.Time) (bool, error) {
+	next, err := nextScheduleRun(schedule, now)
+	if err != nil {
+		return false, err
+	}
+
+	skip := schedule.MisfirePolicy == domain.MisfirePolicySkip && schedule.IsMisfired(now, c.misfireThreshold)
+	var (
+		taskID    string
+		createErr error
+	)
+	if !skip {
+		created, err := c.createTask.Handle(ctx, createTaskRequest(schedule.Template))
+		if err != nil {
+			createErr = err
+		} else {
+			taskID = created.ID
+		}
+	}
+
+	// Срабатывание, при котором задачу создать не удалось, считается пропущенным:
+	// иначе расписание повторяло бы его на каждой проверке
+	_, err = c.repo.Update(schedule.ID, func(current *domain.Schedule) error {
+		switch {
+		case skip:
+			current.MissedRuns++
+		case createErr != nil:
+			current.MissedRuns++
+			current.LastError = createErr.Error()
+		default:
+			current.LastRunAt = &now
+			current.LastTaskID = taskID
+			current.LastError = ""
+		}
+		current.NextRunAt = next
+		return nil
+	})
+	// Расписание удалили во время срабатывания
+	if errors.Is(err, domain.ErrScheduleNotFound) {
+		err = nil
+	}
+	if err = errors.Join(createErr, err); err != nil {
+		return false, err
+	}
+
+	if skip {
+		c.logger.Warn("Schedule misfire skipped",
+			slog.String("schedule_id", schedule.ID),
+			slog.Time("missed_run_at", schedule.NextRunAt),
+		)
+	}
+	return !skip, nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type heartbeatTaskCommnad struct {
+	logger   domain.ILogger
+	repo     domain.IInMemoRepository
+	leaseTTL time.Duration
+}
+
+type HeartbeatTaskCommnad decorator.CommandHandlerDecorator[dto.HeartbeatRequest, domain.Task]
+
+func NewHeartbeatTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, leaseTTL time.Duration) decorator.CommandHandlerDecorator[dto.HeartbeatRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.HeartbeatRequest, domain.Task](
+		heartbeatTaskCommnad{
+			logger:   logger,
+			repo:     repo,
+			leaseTTL: leaseTTL,
+		},
+		logger,
+	)
+
+}
+
+func (c heartbeatTaskCommnad) Handle(ctx context.Context, request dto.HeartbeatRequest) (domain.Task, error) {
+	lease := c.leaseTTL
+	if request.LeaseSeconds > 0 {
+		lease = time.Duration(request.LeaseSeconds) * time.Second
+	}
+	return c.repo.Update(request.Id, func(task *domain.Task) error {
+		return task.ExtendLease(request.WorkerID, lease, time.Now())
+	})
+}
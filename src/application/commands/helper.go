@@ -2,6 +2,7 @@ package commands
 
 import (
 	"github.com/google/uuid"
+	"svc-task_master/src/common/cron"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
 	"time"
@@ -11,10 +12,15 @@ func createTask(request dto.TaskRequest) domain.Task {
 	now := time.Now()
 	id := uuid.New().String()
 
+	status := domain.TaskStatusPending
+	if request.ScheduledAt != nil && request.ScheduledAt.After(now) {
+		status = domain.TaskStatusScheduled
+	}
+
 	return domain.Task{
 		ID:           id,
 		Type:         request.Type,
-		Status:       domain.TaskStatusPending,
+		Status:       status,
 		Priority:     domain.TaskPriority(request.Priority),
 		CreatedAt:    now,
 		UpdatedAt:    now,
@@ -23,6 +29,7 @@ func createTask(request dto.TaskRequest) domain.Task {
 		Metadata:     request.Metadata,
 		RetryCount:   request.RetryCount,
 		MaxRetries:   request.MaxRetries,
+		RetryPolicy:  createRetryPolicy(request.RetryPolicy),
 		ParentTaskID: request.ParentTaskID,
 		DependsOn:    request.DependsOn,
 		Queue:        request.Queue,
@@ -34,3 +41,63 @@ func createTask(request dto.TaskRequest) domain.Task {
 		Output:     nil,
 	}
 }
+
+func createRetryPolicy(request *dto.RetryPolicy) *domain.RetryPolicy {
+	if request == nil {
+		return nil
+	}
+	return &domain.RetryPolicy{
+		Strategy:   domain.RetryStrategy(request.Strategy),
+		DelayMs:    request.DelayMs,
+		MaxDelayMs: request.MaxDelayMs,
+		Jitter:     request.Jitter,
+	}
+}
+
+func createTaskTemplate(request dto.TaskRequest) domain.TaskTemplate {
+	return domain.TaskTemplate{
+		Type:         request.Type,
+		Priority:     domain.TaskPriority(request.Priority),
+		Payload:      request.Payload,
+		Metadata:     request.Metadata,
+		MaxRetries:   request.MaxRetries,
+		RetryPolicy:  createRetryPolicy(request.RetryPolicy),
+		ParentTaskID: request.ParentTaskID,
+		DependsOn:    request.DependsOn,
+		Queue:        request.Queue,
+	}
+}
+
+func createTaskRequest(template domain.TaskTemplate) dto.TaskRequest {
+	request := dto.TaskRequest{
+		Type:         template.Type,
+		Priority:     string(template.Priority),
+		Payload:      template.Payload,
+		Metadata:     template.Metadata,
+		MaxRetries:   template.MaxRetries,
+		ParentTaskID: template.ParentTaskID,
+		DependsOn:    template.DependsOn,
+		Queue:        template.Queue,
+	}
+	if template.RetryPolicy != nil {
+		request.RetryPolicy = &dto.RetryPolicy{
+			Strategy:   string(template.RetryPolicy.Strategy),
+			DelayMs:    template.RetryPolicy.DelayMs,
+			MaxDelayMs: template.RetryPolicy.MaxDelayMs,
+			Jitter:     template.RetryPolicy.Jitter,
+		}
+	}
+	return request
+}
+
+func nextScheduleRun(schedule domain.Schedule, after time.Time) (time.Time, error) {
+	expr, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return expr.Next(after.In(loc)), nil
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+// TaskLifecycle применяет последствия смены статуса задачи к связанным с ней задачам
+type TaskLifecycle struct {
+	logger  domain.ILogger
+	repo    domain.IInMemoRepository
+	archive domain.ITaskArchive
+}
+
+func NewTaskLifecycle(logger domain.ILogger, repo domain.IInMemoRepository, archive domain.ITaskArchive) TaskLifecycle {
+	return TaskLifecycle{
+		logger:  logger,
+		repo:    repo,
+		archive: archive,
+	}
+}
+
+func (l TaskLifecycle) afterTransition(ctx context.Context, task domain.Task) {
+	switch task.Status {
+	case domain.TaskStatusBlocked:
+		l.settleBlocked(ctx, task)
+	case domain.TaskStatusCompleted:
+		l.unblockDependents(ctx, task)
+	case domain.TaskStatusFailed, domain.TaskStatusCancelled:
+		l.failDependents(ctx, task)
+	}
+	if task.ParentTaskID != "" {
+		l.refreshParent(ctx, task.ParentTaskID, task.ID)
+	}
+}
+
+func (l TaskLifecycle) refreshParent(ctx context.Context, parentID string, childID string) {
+	children, err := l.repo.GetChildren(ctx, parentID)
+	if err != nil {
+		l.logger.Error("Failed to get child tasks",
+			slog.String("task_id", parentID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	aggregate := domain.AggregateChildren(children)
+	_, err = updateRelated(ctx, l.repo, parentID, childID, func(t *domain.Task) error {
+		t.Children = &aggregate
+		return nil
+	})
+	// Родитель мог быть уже перенесен в архив по правилам хранения
+	if err != nil && !errors.Is(err, domain.ErrTaskNotFound) {
+		l.logger.Warn("Failed to update parent task aggregate",
+			slog.String("task_id", parentID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (l TaskLifecycle) unblockDependents(ctx context.Context, task domain.Task) {
+	dependents, err := l.repo.GetDependents(ctx, task.ID)
+	if err != nil {
+		l.logger.Error("Failed to get dependent tasks",
+			slog.String("task_id", task.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	for _, dependent := range dependents {
+		if dependent.Status != domain.TaskStatusBlocked || !l.dependenciesCompleted(ctx, dependent) {
+			continue
+		}
+		l.unblock(ctx, dependent.ID, task.ID)
+	}
+}
+
+func (l TaskLifecycle) unblock(ctx context.Context, id string, causedBy string) {
+	unblocked, err := updateRelated(ctx, l.repo, id, causedBy, func(t *domain.Task) error {
+		return t.Unblock(time.Now())
+	})
+	if err != nil {
+		// Задачу уже разблокировало завершение другой зависимости
+		return
+	}
+	l.logger.Info("Task unblocked",
+		slog.String("task_id", unblocked.ID),
+		slog.String("new_status", string(unblocked.Status)),
+	)
+	l.afterTransition(ctx, unblocked)
+}
+
+func (l TaskLifecycle) failDependents(ctx context.Context, task domain.Task) {
+	dependents, err := l.repo.GetDependents(ctx, task.ID)
+	if err != nil {
+		l.logger.Error("Failed to get dependent tasks",
+			slog.String("task_id", task.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	for _, dependent := range dependents {
+		if dependent.Status != domain.TaskStatusBlocked {
+			continue
+		}
+		l.failDependent(ctx, dependent.ID, task)
+	}
+}
+
+func (l TaskLifecycle) failDependent(ctx context.Context, id string, dependency domain.Task) {
+	failed, err := updateRelated(ctx, l.repo, id, dependency.ID, func(t *domain.Task) error {
+		return t.FailDependency(dependency, time.Now())
+	})
+	if err != nil {
+		return
+	}
+	l.logger.Warn("Task failed due to dependency failure or cancellation",
+		slog.String("task_id", failed.ID),
+		slog.String("dependency_id", dependency.ID),
+	)
+	l.afterTransition(ctx, failed)
+}
+
+// afterRequeue возвращает в blocked задачи, завершенные из-за ошибки задачи,
+// которую вернули из DLQ в очередь, и применяет последствия requeue
+func (l TaskLifecycle) afterRequeue(ctx context.Context, task domain.Task) {
+	l.restoreDependents(ctx, task)
+	l.afterTransition(ctx, task)
+}
+
+func (l TaskLifecycle) restoreDependents(ctx context.Context, task domain.Task) {
+	dependents, err := l.repo.GetDependents(ctx, task.ID)
+	if err != nil {
+		l.logger.Error("Failed to get dependent tasks",
+			slog.String("task_id", task.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	for _, dependent := range dependents {
+		// Задача, у которой есть и другие завершившиеся с ошибкой зависимости, остается в failed
+		if !dependent.FailedByDependency() || l.otherDependencyFailed(ctx, dependent, task.ID) {
+			continue
+		}
+		restored, err := updateRelated(ctx, l.repo, dependent.ID, task.ID, func(t *domain.Task) error {
+			return t.RestoreDependency(time.Now())
+		})
+		if err != nil {
+			continue
+		}
+		l.logger.Info("Task restored after dependency requeue",
+			slog.String("task_id", restored.ID),
+			slog.String("dependency_id", task.ID),
+		)
+		l.restoreDependents(ctx, restored)
+		l.afterTransition(ctx, restored)
+	}
+}
+
+func (l TaskLifecycle) otherDependencyFailed(ctx context.Context, task domain.Task, exceptID string) bool {
+	for _, id := range task.DependsOn {
+		if id == exceptID {
+			continue
+		}
+		dep, ok := l.find(ctx, id)
+		if ok && (dep.Status == domain.TaskStatusFailed || dep.Status == domain.TaskStatusCancelled) {
+			return true
+		}
+	}
+	return false
+}
+
+// settleBlocked повторно проверяет зависимости заблокированной задачи. Зависимость
+// может завершиться между проверкой зависимостей новой задачи и ее сохранением, и
+// тогда ее завершение не застает задачу в хранилище
+func (l TaskLifecycle) settleBlocked(ctx context.Context, task domain.Task) {
+	for _, id := range task.DependsOn {
+		dep, ok := l.find(ctx, id)
+		if ok && (dep.Status == domain.TaskStatusFailed || dep.Status == domain.TaskStatusCancelled) {
+			l.failDependent(ctx, task.ID, dep)
+			return
+		}
+	}
+	if l.dependenciesCompleted(ctx, task) {
+		l.unblock(ctx, task.ID, "")
+	}
+}
+
+func (l TaskLifecycle) dependenciesCompleted(ctx context.Context, task domain.Task) bool {
+	for _, id := range task.DependsOn {
+		dep, ok := l.find(ctx, id)
+		if !ok || dep.Status != domain.TaskStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// find возвращает связанную задачу — зависимость или родителя. Завершенная задача может
+// быть удалена по правилам хранения раньше связанных с ней, тогда она берется из архива
+func (l TaskLifecycle) find(ctx context.Context, id string) (domain.Task, bool) {
+	if dep, ok := l.repo.Get(id); ok {
+		return dep, true
+	}
+	archived, err := l.archive.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, domain.ErrArchivedTaskNotFound) && !errors.Is(err, domain.ErrArchiveDisabled) {
+			l.logger.Error("Failed to get archived task",
+				slog.String("task_id", id),
+				slog.String("error", err.Error()),
+			)
+		}
+		return domain.Task{}, false
+	}
+	return archived.Task, true
+}
+
+// updateRelated изменяет задачу, затронутую командой помимо ее основной задачи, и отмечает
+// изменение для журнала аудита команды; causedBy — задача, изменение которой его повлекло
+func updateRelated(ctx context.Context, repo domain.IInMemoRepository, id string, causedBy string, fn func(task *domain.Task) error) (domain.Task, error) {
+	var before domain.TaskStatus
+	updated, err := repo.Update(id, func(task *domain.Task) error {
+		before = task.Status
+		return fn(task)
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	domain.RecordAuditChange(ctx, domain.AuditEntry{
+		TaskID:       id,
+		StatusBefore: before,
+		StatusAfter:  updated.Status,
+		CausedBy:     causedBy,
+	})
+	return updated, nil
+}
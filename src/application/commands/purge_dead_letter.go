@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type purgeDeadLetterCommnad struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type PurgeDeadLetterCommnad decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task]
+
+func NewPurgeDeadLetterCommnad(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeadLetterRequest, domain.Task](
+		purgeDeadLetterCommnad{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c purgeDeadLetterCommnad) Handle(ctx context.Context, request dto.DeadLetterRequest) (domain.Task, error) {
+	return c.repo.Delete(request.Id, inDeadLetterQueue(request.Queue))
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type purgeDeadLettersCommnad struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type PurgeDeadLettersCommnad decorator.CommandHandlerDecorator[dto.DeadLettersRequest, []string]
+
+func NewPurgeDeadLettersCommnad(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.DeadLettersRequest, []string] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeadLettersRequest, []string](
+		purgeDeadLettersCommnad{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+// Handle удаляет задачи из DLQ очереди и возвращает их ID. Задачи, которых
+// уже нет в DLQ очереди, пропускаются
+func (c purgeDeadLettersCommnad) Handle(ctx context.Context, request dto.DeadLettersRequest) ([]string, error) {
+	ids, err := deadLetterIDs(ctx, c.repo, request.Queue, request.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]string, 0, len(ids))
+	for _, id := range ids {
+		deleted, err := c.repo.Delete(id, inDeadLetterQueue(request.Queue))
+		if errors.Is(err, domain.ErrDeadLetterNotFound) || errors.Is(err, domain.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		domain.RecordAuditChange(ctx, domain.AuditEntry{TaskID: id, StatusBefore: deleted.Status})
+		purged = append(purged, id)
+	}
+
+	c.logger.Info("Dead-letter tasks purged",
+		slog.String("queue", request.Queue),
+		slog.Int("count", len(purged)),
+	)
+	return purged, nil
+}
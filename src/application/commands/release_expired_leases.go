@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type releaseExpiredLeasesCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type ReleaseExpiredLeasesCommnad decorator.CommandHandlerDecorator[dto.ReleaseExpiredLeasesRequest, int]
+
+func NewReleaseExpiredLeasesCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.ReleaseExpiredLeasesRequest, int] {
+	return decorator.ApplyCommandLoggerDecorator[dto.ReleaseExpiredLeasesRequest, int](
+		releaseExpiredLeasesCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+func (c releaseExpiredLeasesCommnad) Handle(ctx context.Context, request dto.ReleaseExpiredLeasesRequest) (int, error) {
+	processing, err := c.repo.GetAllFilterStatus(ctx, domain.TaskStatusProcessing)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, candidate := range processing {
+		if !candidate.LeaseExpired(request.Now) {
+			continue
+		}
+		workerID := candidate.WorkerID
+		task, err := updateRelated(ctx, c.repo, candidate.ID, "", func(task *domain.Task) error {
+			return task.ExpireLease(request.Now)
+		})
+		// Воркер успел продлить аренду или завершить задачу
+		if errors.Is(err, domain.ErrLeaseNotHeld) || errors.Is(err, domain.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+
+		released++
+		c.lifecycle.afterTransition(ctx, task)
+		c.logger.Warn("Task lease expired",
+			slog.String("task_id", task.ID),
+			slog.String("worker_id", workerID),
+			slog.String("new_status", string(task.Status)),
+			slog.Int("retry_count", task.RetryCount),
+		)
+	}
+	return released, nil
+}
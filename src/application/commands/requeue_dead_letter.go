@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type requeueDeadLetterCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type RequeueDeadLetterCommnad decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task]
+
+func NewRequeueDeadLetterCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeadLetterRequest, domain.Task](
+		requeueDeadLetterCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+func (c requeueDeadLetterCommnad) Handle(ctx context.Context, request dto.DeadLetterRequest) (domain.Task, error) {
+	task, err := c.repo.Update(request.Id, func(task *domain.Task) error {
+		return task.Requeue(request.Queue, time.Now())
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	c.lifecycle.afterRequeue(ctx, task)
+	return task, nil
+}
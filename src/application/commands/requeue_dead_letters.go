@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+type requeueDeadLettersCommnad struct {
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
+}
+
+type RequeueDeadLettersCommnad decorator.CommandHandlerDecorator[dto.DeadLettersRequest, []string]
+
+func NewRequeueDeadLettersCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.DeadLettersRequest, []string] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeadLettersRequest, []string](
+		requeueDeadLettersCommnad{
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
+		},
+		logger,
+	)
+
+}
+
+// Handle возвращает задачи из DLQ в очередь и возвращает их ID. Задачи, которых
+// уже нет в DLQ очереди, пропускаются
+func (c requeueDeadLettersCommnad) Handle(ctx context.Context, request dto.DeadLettersRequest) ([]string, error) {
+	ids, err := deadLetterIDs(ctx, c.repo, request.Queue, request.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	requeued := make([]string, 0, len(ids))
+	for _, id := range ids {
+		task, err := updateRelated(ctx, c.repo, id, "", func(task *domain.Task) error {
+			return task.Requeue(request.Queue, time.Now())
+		})
+		if errors.Is(err, domain.ErrDeadLetterNotFound) || errors.Is(err, domain.ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return requeued, err
+		}
+		c.lifecycle.afterRequeue(ctx, task)
+		requeued = append(requeued, task.ID)
+	}
+
+	c.logger.Info("Dead-letter tasks requeued",
+		slog.String("queue", request.Queue),
+		slog.Int("count", len(requeued)),
+	)
+	return requeued, nil
+}
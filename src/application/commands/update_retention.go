@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type updateRetentionCommnad struct {
+	logger domain.ILogger
+	repo   domain.IRetentionRepository
+}
+
+type UpdateRetentionCommnad decorator.CommandHandlerDecorator[dto.UpdateRetentionRequest, domain.Retention]
+
+func NewUpdateRetentionCommnad(logger domain.ILogger, repo domain.IRetentionRepository) decorator.CommandHandlerDecorator[dto.UpdateRetentionRequest, domain.Retention] {
+	return decorator.ApplyCommandLoggerDecorator[dto.UpdateRetentionRequest, domain.Retention](
+		updateRetentionCommnad{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c updateRetentionCommnad) Handle(ctx context.Context, request dto.UpdateRetentionRequest) (domain.Retention, error) {
+	policy := domain.RetentionPolicy{Rules: request.Rules}
+	c.repo.SetRetentionPolicy(policy)
+	c.logger.Info("Retention policy updated", slog.Int("rules", len(policy.Rules)))
+
+	return domain.Retention{
+		Policy:    c.repo.GetRetentionPolicy(),
+		Evictions: c.repo.GetEvictionStats(),
+	}, nil
+}
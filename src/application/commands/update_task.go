@@ -5,27 +5,67 @@ import (
 	"svc-task_master/src/common/decorator"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
 )
 
 type updateTaskCommnad struct {
-	logger domain.ILogger
-	repo   domain.IInMemoRepository
+	logger    domain.ILogger
+	repo      domain.IInMemoRepository
+	lifecycle TaskLifecycle
 }
 
-type UpdateTaskCommnad decorator.CommandHandlerDecorator[dto.UpdateTaskStatusRequest, any]
+type UpdateTaskCommnad decorator.CommandHandlerDecorator[dto.UpdateTaskStatusRequest, domain.Task]
 
-func NewUpdateTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.UpdateTaskStatusRequest, any] {
-	return decorator.ApplyCommandLoggerDecorator[dto.UpdateTaskStatusRequest, any](
+func NewUpdateTaskCommnad(logger domain.ILogger, repo domain.IInMemoRepository, lifecycle TaskLifecycle) decorator.CommandHandlerDecorator[dto.UpdateTaskStatusRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.UpdateTaskStatusRequest, domain.Task](
 		updateTaskCommnad{
-			logger: logger,
-			repo:   repo,
+			logger:    logger,
+			repo:      repo,
+			lifecycle: lifecycle,
 		},
 		logger,
 	)
 
 }
 
-func (c updateTaskCommnad) Handle(ctx context.Context, request dto.UpdateTaskStatusRequest) (any, error) {
-	c.repo.UpdateStatus(request.Id, domain.TaskStatus(request.Status))
-	return nil, nil
+func (c updateTaskCommnad) Handle(ctx context.Context, request dto.UpdateTaskStatusRequest) (domain.Task, error) {
+	status := domain.TaskStatus(request.Status)
+	transition := func(task *domain.Task) error {
+		if !manualTransitionAllowed(task.Status, status) {
+			return &domain.TransitionError{From: task.Status, To: status}
+		}
+		if status == domain.TaskStatusFailed {
+			return task.Fail(nil, time.Now())
+		}
+		return task.TransitionTo(status, time.Now())
+	}
+
+	var task domain.Task
+	var err error
+	if request.ExpectedVersion != nil {
+		task, err = c.repo.CompareAndSwap(request.Id, *request.ExpectedVersion, transition)
+	} else {
+		task, err = c.repo.Update(request.Id, transition)
+	}
+	if err != nil {
+		return domain.Task{}, err
+	}
+	c.lifecycle.afterTransition(ctx, task)
+	return task, nil
+}
+
+// manualTransitionAllowed отсекает переходы, которые PUT не должен выполнять в обход
+// остальных правил: из failed задачу возвращает только requeue из DLQ, из blocked
+// ее выводит выполнение зависимостей, а результат выполнения задачи в processing
+// принимается только от воркера, удерживающего аренду, через complete и fail.
+// Такие задачи вручную можно только отменить
+func manualTransitionAllowed(from domain.TaskStatus, to domain.TaskStatus) bool {
+	switch from {
+	case domain.TaskStatusFailed:
+		return false
+	case domain.TaskStatusBlocked, domain.TaskStatusProcessing:
+		return to == domain.TaskStatusCancelled
+	default:
+		return true
+	}
 }
@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getArchivedTaskQuery struct {
+	logger  domain.ILogger
+	archive domain.ITaskArchive
+}
+
+type GetArchivedTaskQuery decorator.CommandHandlerDecorator[dto.GetArchivedTaskRequest, domain.ArchivedTask]
+
+func NewGetArchivedTaskQuery(logger domain.ILogger, archive domain.ITaskArchive) decorator.CommandHandlerDecorator[dto.GetArchivedTaskRequest, domain.ArchivedTask] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetArchivedTaskRequest, domain.ArchivedTask](
+		getArchivedTaskQuery{
+			logger:  logger,
+			archive: archive,
+		},
+		logger,
+	)
+
+}
+
+func (c getArchivedTaskQuery) Handle(ctx context.Context, request dto.GetArchivedTaskRequest) (domain.ArchivedTask, error) {
+	return c.archive.Get(ctx, request.ID)
+}
@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getArchivedTasksQuery struct {
+	logger  domain.ILogger
+	archive domain.ITaskArchive
+}
+
+type GetArchivedTasksQuery decorator.CommandHandlerDecorator[dto.GetArchivedTasksRequest, []domain.ArchivedTask]
+
+func NewGetArchivedTasksQuery(logger domain.ILogger, archive domain.ITaskArchive) decorator.CommandHandlerDecorator[dto.GetArchivedTasksRequest, []domain.ArchivedTask] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetArchivedTasksRequest, []domain.ArchivedTask](
+		getArchivedTasksQuery{
+			logger:  logger,
+			archive: archive,
+		},
+		logger,
+	)
+
+}
+
+func (c getArchivedTasksQuery) Handle(ctx context.Context, request dto.GetArchivedTasksRequest) ([]domain.ArchivedTask, error) {
+	return c.archive.Find(ctx, request.FromTime, request.ToTime, request.MaxItems)
+}
@@ -0,0 +1,35 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getAuditQuery struct {
+	logger domain.ILogger
+	audit  domain.IAuditRepository
+}
+
+type GetAuditQuery decorator.CommandHandlerDecorator[dto.GetAuditRequest, []domain.AuditEntry]
+
+func NewGetAuditQuery(logger domain.ILogger, audit domain.IAuditRepository) decorator.CommandHandlerDecorator[dto.GetAuditRequest, []domain.AuditEntry] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetAuditRequest, []domain.AuditEntry](
+		getAuditQuery{
+			logger: logger,
+			audit:  audit,
+		},
+		logger,
+	)
+
+}
+
+func (c getAuditQuery) Handle(ctx context.Context, request dto.GetAuditRequest) ([]domain.AuditEntry, error) {
+	return c.audit.Find(ctx, domain.AuditFilter{
+		Actor: request.Actor,
+		From:  request.FromTime,
+		To:    request.ToTime,
+		Limit: request.MaxItems,
+	})
+}
@@ -0,0 +1,34 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getDeadLetterQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type GetDeadLetterQuery decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task]
+
+func NewGetDeadLetterQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.DeadLetterRequest, domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.DeadLetterRequest, domain.Task](
+		getDeadLetterQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getDeadLetterQuery) Handle(ctx context.Context, request dto.DeadLetterRequest) (domain.Task, error) {
+	task, ok := c.repo.Get(request.Id)
+	if !ok || !task.InDeadLetterQueue(request.Queue) {
+		return domain.Task{}, domain.ErrDeadLetterNotFound
+	}
+	return task, nil
+}
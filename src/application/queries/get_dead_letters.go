@@ -0,0 +1,35 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getDeadLettersQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type GetDeadLettersQuery decorator.CommandHandlerDecorator[dto.GetDeadLettersRequest, domain.TaskPage]
+
+func NewGetDeadLettersQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetDeadLettersRequest, domain.TaskPage] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetDeadLettersRequest, domain.TaskPage](
+		getDeadLettersQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getDeadLettersQuery) Handle(ctx context.Context, request dto.GetDeadLettersRequest) (domain.TaskPage, error) {
+	query := request.Query
+	query.Filter = domain.TaskFilter{Queue: request.Queue, Status: domain.TaskStatusFailed}
+	query.Predicate = func(task *domain.Task) bool {
+		return task.InDeadLetterQueue(request.Queue)
+	}
+	return c.repo.ListTasks(ctx, query)
+}
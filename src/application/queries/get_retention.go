@@ -0,0 +1,34 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getRetentionQuery struct {
+	logger domain.ILogger
+	repo   domain.IRetentionRepository
+}
+
+type GetRetentionQuery decorator.CommandHandlerDecorator[dto.GetRetentionRequest, domain.Retention]
+
+func NewGetRetentionQuery(logger domain.ILogger, repo domain.IRetentionRepository) decorator.CommandHandlerDecorator[dto.GetRetentionRequest, domain.Retention] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetRetentionRequest, domain.Retention](
+		getRetentionQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getRetentionQuery) Handle(ctx context.Context, request dto.GetRetentionRequest) (domain.Retention, error) {
+	return domain.Retention{
+		Policy:        c.repo.GetRetentionPolicy(),
+		Evictions:     c.repo.GetEvictionStats(),
+		DeadLetterTTL: c.repo.GetDeadLetterTTL(),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getSchedulesQuery struct {
+	logger domain.ILogger
+	repo   domain.IScheduleRepository
+}
+
+type GetSchedulesQuery decorator.CommandHandlerDecorator[dto.GetSchedulesRequest, []domain.Schedule]
+
+func NewGetSchedulesQuery(logger domain.ILogger, repo domain.IScheduleRepository) decorator.CommandHandlerDecorator[dto.GetSchedulesRequest, []domain.Schedule] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetSchedulesRequest, []domain.Schedule](
+		getSchedulesQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getSchedulesQuery) Handle(ctx context.Context, request dto.GetSchedulesRequest) ([]domain.Schedule, error) {
+	return c.repo.GetAll(ctx)
+}
@@ -2,7 +2,6 @@ package queries
 
 import (
 	"context"
-	"errors"
 	"svc-task_master/src/common/decorator"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
@@ -29,7 +28,7 @@ func NewGetTaskIdQuery(logger domain.ILogger, repo domain.IInMemoRepository) dec
 func (c getTaskIdQuery) Handle(ctx context.Context, request dto.GetTaskRequest) (domain.Task, error) {
 	task, ok := c.repo.Get(request.ID)
 	if !ok {
-		return domain.Task{}, errors.New("task not found")
+		return domain.Task{}, domain.ErrTaskNotFound
 	}
 	return task, nil
 }
@@ -0,0 +1,37 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getTaskAttemptsQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type GetTaskAttemptsQuery decorator.CommandHandlerDecorator[dto.GetTaskAttemptsRequest, []domain.TaskAttempt]
+
+func NewGetTaskAttemptsQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetTaskAttemptsRequest, []domain.TaskAttempt] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskAttemptsRequest, []domain.TaskAttempt](
+		getTaskAttemptsQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getTaskAttemptsQuery) Handle(ctx context.Context, request dto.GetTaskAttemptsRequest) ([]domain.TaskAttempt, error) {
+	task, ok := c.repo.Get(request.ID)
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	if task.Attempts == nil {
+		return []domain.TaskAttempt{}, nil
+	}
+	return task.Attempts, nil
+}
@@ -0,0 +1,31 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getTaskAuditQuery struct {
+	logger domain.ILogger
+	audit  domain.IAuditRepository
+}
+
+type GetTaskAuditQuery decorator.CommandHandlerDecorator[dto.GetTaskAuditRequest, []domain.AuditEntry]
+
+func NewGetTaskAuditQuery(logger domain.ILogger, audit domain.IAuditRepository) decorator.CommandHandlerDecorator[dto.GetTaskAuditRequest, []domain.AuditEntry] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskAuditRequest, []domain.AuditEntry](
+		getTaskAuditQuery{
+			logger: logger,
+			audit:  audit,
+		},
+		logger,
+	)
+
+}
+
+// Handle возвращает журнал задачи и после ее удаления по правилам хранения
+func (c getTaskAuditQuery) Handle(ctx context.Context, request dto.GetTaskAuditRequest) ([]domain.AuditEntry, error) {
+	return c.audit.Find(ctx, domain.AuditFilter{TaskID: request.ID})
+}
@@ -0,0 +1,33 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getTaskChildrenQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type GetTaskChildrenQuery decorator.CommandHandlerDecorator[dto.GetTaskChildrenRequest, []domain.Task]
+
+func NewGetTaskChildrenQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetTaskChildrenRequest, []domain.Task] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskChildrenRequest, []domain.Task](
+		getTaskChildrenQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getTaskChildrenQuery) Handle(ctx context.Context, request dto.GetTaskChildrenRequest) ([]domain.Task, error) {
+	if _, ok := c.repo.Get(request.ID); !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	return c.repo.GetChildren(ctx, request.ID)
+}
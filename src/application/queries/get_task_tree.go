@@ -0,0 +1,54 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type getTaskTreeQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type GetTaskTreeQuery decorator.CommandHandlerDecorator[dto.GetTaskTreeRequest, domain.TaskTree]
+
+func NewGetTaskTreeQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetTaskTreeRequest, domain.TaskTree] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskTreeRequest, domain.TaskTree](
+		getTaskTreeQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c getTaskTreeQuery) Handle(ctx context.Context, request dto.GetTaskTreeRequest) (domain.TaskTree, error) {
+	task, ok := c.repo.Get(request.ID)
+	if !ok {
+		return domain.TaskTree{}, domain.ErrTaskNotFound
+	}
+	return c.buildTree(ctx, task)
+}
+
+func (c getTaskTreeQuery) buildTree(ctx context.Context, task domain.Task) (domain.TaskTree, error) {
+	children, err := c.repo.GetChildren(ctx, task.ID)
+	if err != nil {
+		return domain.TaskTree{}, err
+	}
+
+	tree := domain.TaskTree{
+		Task:     task,
+		Children: make([]domain.TaskTree, 0, len(children)),
+	}
+	for _, child := range children {
+		subtree, err := c.buildTree(ctx, child)
+		if err != nil {
+			return domain.TaskTree{}, err
+		}
+		tree.Children = append(tree.Children, subtree)
+	}
+	return tree, nil
+}
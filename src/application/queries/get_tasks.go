@@ -2,7 +2,6 @@ package queries
 
 import (
 	"context"
-	_ "errors"
 	"svc-task_master/src/common/decorator"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
@@ -13,10 +12,10 @@ type getTasksQuery struct {
 	repo   domain.IInMemoRepository
 }
 
-type GetTasksQuery decorator.CommandHandlerDecorator[dto.GetTaskWhithFiltersRequest, []domain.Task]
+type GetTasksQuery decorator.CommandHandlerDecorator[dto.GetTaskWhithFiltersRequest, domain.TaskPage]
 
-func NewGetTasksQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetTaskWhithFiltersRequest, []domain.Task] {
-	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskWhithFiltersRequest, []domain.Task](
+func NewGetTasksQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.GetTaskWhithFiltersRequest, domain.TaskPage] {
+	return decorator.ApplyCommandLoggerDecorator[dto.GetTaskWhithFiltersRequest, domain.TaskPage](
 		getTasksQuery{
 			logger: logger,
 			repo:   repo,
@@ -26,7 +25,6 @@ func NewGetTasksQuery(logger domain.ILogger, repo domain.IInMemoRepository) deco
 
 }
 
-func (c getTasksQuery) Handle(ctx context.Context, request dto.GetTaskWhithFiltersRequest) ([]domain.Task, error) {
-	task, err := c.repo.GetAllFilterStatus(ctx, domain.TaskStatus(request.Status))
-	return task, err
+func (c getTasksQuery) Handle(ctx context.Context, request dto.GetTaskWhithFiltersRequest) (domain.TaskPage, error) {
+	return c.repo.ListTasks(ctx, request.Query)
 }
@@ -0,0 +1,39 @@
+package queries
+
+import (
+	"context"
+	"svc-task_master/src/application/search"
+	"svc-task_master/src/common/decorator"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+type searchTasksQuery struct {
+	logger domain.ILogger
+	repo   domain.IInMemoRepository
+}
+
+type SearchTasksQuery decorator.CommandHandlerDecorator[dto.SearchTasksRequest, domain.TaskPage]
+
+func NewSearchTasksQuery(logger domain.ILogger, repo domain.IInMemoRepository) decorator.CommandHandlerDecorator[dto.SearchTasksRequest, domain.TaskPage] {
+	return decorator.ApplyCommandLoggerDecorator[dto.SearchTasksRequest, domain.TaskPage](
+		searchTasksQuery{
+			logger: logger,
+			repo:   repo,
+		},
+		logger,
+	)
+
+}
+
+func (c searchTasksQuery) Handle(ctx context.Context, request dto.SearchTasksRequest) (domain.TaskPage, error) {
+	expr, err := search.Parse(request.Q)
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+
+	query := request.Query
+	query.Filter = expr.Filter()
+	query.Predicate = expr.Match
+	return c.repo.ListTasks(ctx, query)
+}
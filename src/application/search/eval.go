@@ -0,0 +1,294 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+const (
+	opIn    = "IN"
+	opNotIn = "NOT IN"
+)
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindTime
+)
+
+// operand значение поля задачи или литерал запроса. Приоритет сравнивается
+// по весу, поэтому хранится как число вместе с исходной строкой
+type operand struct {
+	kind valueKind
+	str  string
+	num  float64
+	time time.Time
+}
+
+type field struct {
+	// ordered поле поддерживает операторы <, <=, >, >=
+	ordered bool
+	get     func(task *domain.Task) (operand, bool)
+	literal func(tok token) (operand, error)
+	// index переносит условие равенства в фильтр хранилища; nil для неиндексируемых полей
+	index func(filter *domain.TaskFilter, value string)
+}
+
+var fields = map[string]field{
+	"id":       stringField(func(task *domain.Task) string { return task.ID }, nil),
+	"type":     stringField(func(task *domain.Task) string { return task.Type }, func(f *domain.TaskFilter, v string) { f.Type = v }),
+	"queue":    stringField(func(task *domain.Task) string { return task.Queue }, func(f *domain.TaskFilter, v string) { f.Queue = v }),
+	"workerId": stringField(func(task *domain.Task) string { return task.WorkerID }, func(f *domain.TaskFilter, v string) { f.WorkerID = v }),
+	"parentId": stringField(func(task *domain.Task) string { return task.ParentTaskID }, func(f *domain.TaskFilter, v string) { f.ParentTaskID = v }),
+	"status": {
+		get: func(task *domain.Task) (operand, bool) {
+			return operand{kind: kindString, str: string(task.Status)}, true
+		},
+		literal: func(tok token) (operand, error) {
+			if tok.kind != tokenString || !domain.TaskStatus(tok.value).IsValid() {
+				return operand{}, errors.New("must be one of \"blocked\", \"scheduled\", \"pending\", \"processing\", \"completed\", \"failed\", \"retrying\", \"cancelled\"")
+			}
+			return operand{kind: kindString, str: tok.value}, nil
+		},
+		index: func(f *domain.TaskFilter, v string) { f.Status = domain.TaskStatus(v) },
+	},
+	"priority": {
+		ordered: true,
+		get: func(task *domain.Task) (operand, bool) {
+			return operand{kind: kindNumber, str: string(task.Priority), num: float64(task.Priority.Weight())}, true
+		},
+		literal: func(tok token) (operand, error) {
+			priority := domain.TaskPriority(tok.value)
+			switch priority {
+			case domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh, domain.TaskPriorityCritical:
+			default:
+				return operand{}, errors.New("must be one of \"low\", \"medium\", \"high\", \"critical\"")
+			}
+			if tok.kind != tokenString {
+				return operand{}, errors.New("must be a string")
+			}
+			return operand{kind: kindNumber, str: tok.value, num: float64(priority.Weight())}, nil
+		},
+		index: func(f *domain.TaskFilter, v string) { f.Priority = domain.TaskPriority(v) },
+	},
+	"retryCount":     numberField(func(task *domain.Task) int { return task.RetryCount }),
+	"maxRetries":     numberField(func(task *domain.Task) int { return task.MaxRetries }),
+	"createdAt":      timeField(func(task *domain.Task) *time.Time { return &task.CreatedAt }),
+	"updatedAt":      timeField(func(task *domain.Task) *time.Time { return &task.UpdatedAt }),
+	"scheduledAt":    timeField(func(task *domain.Task) *time.Time { return task.ScheduledAt }),
+	"startedAt":      timeField(func(task *domain.Task) *time.Time { return task.StartedAt }),
+	"finishedAt":     timeField(func(task *domain.Task) *time.Time { return task.FinishedAt }),
+	"leaseExpiresAt": timeField(func(task *domain.Task) *time.Time { return task.LeaseExpiresAt }),
+}
+
+const metadataPrefix = "metadata."
+
+func lookupField(name token) (field, error) {
+	if key, ok := strings.CutPrefix(name.text, metadataPrefix); ok && key != "" {
+		return metadataField(key), nil
+	}
+	f, ok := fields[name.text]
+	if !ok {
+		return field{}, syntaxError(name.pos, "unknown field %s", name.text)
+	}
+	return f, nil
+}
+
+func stringField(get func(task *domain.Task) string, index func(filter *domain.TaskFilter, value string)) field {
+	return field{
+		get: func(task *domain.Task) (operand, bool) {
+			value := get(task)
+			return operand{kind: kindString, str: value}, value != ""
+		},
+		literal: func(tok token) (operand, error) {
+			if tok.kind != tokenString {
+				return operand{}, errors.New("must be a string")
+			}
+			return operand{kind: kindString, str: tok.value}, nil
+		},
+		index: index,
+	}
+}
+
+func numberField(get func(task *domain.Task) int) field {
+	return field{
+		ordered: true,
+		get: func(task *domain.Task) (operand, bool) {
+			return operand{kind: kindNumber, num: float64(get(task))}, true
+		},
+		literal: func(tok token) (operand, error) {
+			if tok.kind != tokenNumber {
+				return operand{}, errors.New("must be a number")
+			}
+			return numberLiteral(tok)
+		},
+	}
+}
+
+func timeField(get func(task *domain.Task) *time.Time) field {
+	return field{
+		ordered: true,
+		get: func(task *domain.Task) (operand, bool) {
+			value := get(task)
+			if value == nil {
+				return operand{}, false
+			}
+			return operand{kind: kindTime, time: *value}, true
+		},
+		literal: func(tok token) (operand, error) {
+			t, err := time.Parse(time.RFC3339, tok.value)
+			if tok.kind != tokenString || err != nil {
+				return operand{}, errors.New("must be an RFC3339 time string")
+			}
+			return operand{kind: kindTime, time: t}, nil
+		},
+	}
+}
+
+// metadataField значение метаданных по ключу. Числа сравниваются как числа,
+// остальные значения — в строковом представлении
+func metadataField(key string) field {
+	return field{
+		ordered: true,
+		get: func(task *domain.Task) (operand, bool) {
+			value, ok := task.Metadata[key]
+			if !ok || value == nil {
+				return operand{}, false
+			}
+			switch v := value.(type) {
+			case float64:
+				return operand{kind: kindNumber, num: v, str: strconv.FormatFloat(v, 'f', -1, 64)}, true
+			case int:
+				return operand{kind: kindNumber, num: float64(v), str: strconv.Itoa(v)}, true
+			case string:
+				return operand{kind: kindString, str: v}, true
+			default:
+				return operand{kind: kindString, str: fmt.Sprint(v)}, true
+			}
+		},
+		literal: func(tok token) (operand, error) {
+			if tok.kind == tokenNumber {
+				return numberLiteral(tok)
+			}
+			return operand{kind: kindString, str: tok.value}, nil
+		},
+	}
+}
+
+func numberLiteral(tok token) (operand, error) {
+	num, err := strconv.ParseFloat(tok.value, 64)
+	if err != nil {
+		return operand{}, errors.New("malformed number")
+	}
+	return operand{kind: kindNumber, num: num, str: tok.value}, nil
+}
+
+// compare сравнивает значение поля с литералом; false, если значения несравнимы
+func compare(value, literal operand) (int, bool) {
+	switch {
+	case value.kind == kindTime && literal.kind == kindTime:
+		return value.time.Compare(literal.time), true
+	case value.kind == kindNumber && literal.kind == kindNumber:
+		return compareOrdered(value.num, literal.num), true
+	case literal.kind == kindNumber:
+		num, err := strconv.ParseFloat(value.str, 64)
+		if err != nil {
+			return 0, false
+		}
+		return compareOrdered(num, literal.num), true
+	case literal.kind == kindString:
+		return strings.Compare(value.str, literal.str), true
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type node interface {
+	match(task *domain.Task) bool
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(task *domain.Task) bool {
+	return n.left.match(task) && n.right.match(task)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(task *domain.Task) bool {
+	return n.left.match(task) || n.right.match(task)
+}
+
+type notNode struct {
+	inner node
+}
+
+func (n notNode) match(task *domain.Task) bool {
+	return !n.inner.match(task)
+}
+
+// comparison сравнение поля с литералом. Если у задачи нет значения поля
+// или оно несравнимо с литералом, выполняются только != и NOT IN
+type comparison struct {
+	field  field
+	op     string
+	values []operand
+}
+
+func (n comparison) match(task *domain.Task) bool {
+	value, ok := n.field.get(task)
+	if !ok {
+		return n.op == "!=" || n.op == opNotIn
+	}
+
+	if n.op == opIn || n.op == opNotIn {
+		found := false
+		for _, literal := range n.values {
+			if result, ok := compare(value, literal); ok && result == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (n.op == opIn)
+	}
+
+	result, ok := compare(value, n.values[0])
+	if !ok {
+		return n.op == "!="
+	}
+	switch n.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	default:
+		return result >= 0
+	}
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"reflect"
+	"svc-task_master/src/domain"
+	"testing"
+	"time"
+)
+
+func TestExpressionMatch(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	task := &domain.Task{
+		ID:         "task-1",
+		Type:       "email",
+		Queue:      "billing",
+		Status:     domain.TaskStatusPending,
+		Priority:   domain.TaskPriorityHigh,
+		RetryCount: 2,
+		CreatedAt:  created,
+		Metadata:   map[string]interface{}{"user_id": "123", "attempt": float64(3)},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`queue = "billing"`, true},
+		{`queue != "billing"`, false},
+		{`priority > "medium"`, true},
+		{`priority >= "critical"`, false},
+		{`priority IN ("high", "critical")`, true},
+		{`status NOT IN ("completed", "failed")`, true},
+		{`retryCount > 2`, false},
+		{`retryCount <= 2`, true},
+		{`createdAt >= "2024-01-15T10:00:00Z"`, true},
+		{`createdAt < "2024-01-15T10:00:00Z"`, false},
+		// У задачи нет значения поля: выполняются только != и NOT IN
+		{`startedAt < "2030-01-01T00:00:00Z"`, false},
+		{`startedAt != "2030-01-01T00:00:00Z"`, true},
+		{`workerId = "w1"`, false},
+		{`workerId NOT IN ("w1")`, true},
+		{`metadata.user_id = "123"`, true},
+		{`metadata.user_id = 123`, true},
+		{`metadata.attempt > 2`, true},
+		{`metadata.missing = "x"`, false},
+		// NOT связывает сильнее AND, AND — сильнее OR
+		{`queue = "other" OR queue = "billing" AND type = "email"`, true},
+		{`(queue = "other" OR queue = "billing") AND type = "sms"`, false},
+		{`NOT queue = "other" AND NOT NOT type = "email"`, true},
+		{`queue = "billing" AND NOT (retryCount > 1 OR metadata.user_id = "123")`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := expr.Match(task); got != tt.want {
+				t.Fatalf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  domain.TaskFilter
+	}{
+		{`queue = "billing" AND status = "pending"`, domain.TaskFilter{Queue: "billing", Status: domain.TaskStatusPending}},
+		{`priority IN ("high") AND retryCount > 1`, domain.TaskFilter{Priority: domain.TaskPriorityHigh}},
+		{`priority IN ("high", "critical")`, domain.TaskFilter{}},
+		{`queue = "billing" OR queue = "email"`, domain.TaskFilter{}},
+		{`NOT queue = "billing"`, domain.TaskFilter{}},
+		{`queue != "billing"`, domain.TaskFilter{}},
+		{`id = "task-1" AND parentId = "task-0"`, domain.TaskFilter{ParentTaskID: "task-0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := expr.Filter(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Filter = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenIn
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenIdent:
+		return "field name"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	case tokenOperator:
+		return "operator"
+	case tokenLParen:
+		return "'('"
+	case tokenRParen:
+		return "')'"
+	case tokenComma:
+		return "','"
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	default:
+		return "IN"
+	}
+}
+
+// token лексема запроса; pos — позиция первого символа, считая с 1
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+var keywords = map[string]tokenKind{
+	"AND": tokenAnd,
+	"OR":  tokenOr,
+	"NOT": tokenNot,
+	"IN":  tokenIn,
+}
+
+// lex разбивает запрос на лексемы. Ключевые слова не зависят от регистра,
+// строки заключаются в двойные кавычки и поддерживают экранирование \" и \\
+func lex(query string) ([]token, error) {
+	runes := []rune(query)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenOperator, text: "=", pos: pos})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			} else if r == '<' && i < len(runes) && runes[i] == '>' {
+				op = "!="
+				i++
+			} else if r == '!' {
+				return nil, syntaxError(pos, "unexpected '!', did you mean '!='?")
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+		case r == '"':
+			var value strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					value.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if c == '"' {
+					closed = true
+					break
+				}
+				value.WriteRune(c)
+			}
+			if !closed {
+				return nil, syntaxError(pos, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[pos-1 : i]), value: value.String(), pos: pos})
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			if text == "-" {
+				return nil, syntaxError(pos, "expected number after '-'")
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: text, pos: pos})
+		case isIdentStart(r):
+			start := i
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+			kind, ok := keywords[strings.ToUpper(text)]
+			if !ok {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, value: text, pos: pos})
+		default:
+			return nil, syntaxError(pos, "unexpected character '%c'", r)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '.' || r == '-'
+}
@@ -0,0 +1,257 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"svc-task_master/src/domain"
+)
+
+// Error ошибка разбора запроса; Pos — позиция символа, на котором она обнаружена, считая с 1
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", domain.ErrInvalidQuery, e.Pos, e.Msg)
+}
+
+func (e *Error) Is(target error) bool {
+	return target == domain.ErrInvalidQuery
+}
+
+func syntaxError(pos int, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Expression разобранный поисковый запрос вида
+//
+//	queue = "billing" AND priority IN ("high", "critical") AND NOT (retryCount > 2 OR metadata.user_id = "123")
+//
+// Приоритет операторов: NOT, затем AND, затем OR
+type Expression struct {
+	root   node
+	filter domain.TaskFilter
+}
+
+// Parse разбирает запрос и проверяет имена полей и значения
+func Parse(query string) (*Expression, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, syntaxError(1, "query is empty")
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, syntaxError(tok.pos, "unexpected %s", describe(tok))
+	}
+
+	expr := &Expression{root: root}
+	plan(root, &expr.filter)
+	return expr, nil
+}
+
+// Match сообщает, удовлетворяет ли задача запросу
+func (e *Expression) Match(task *domain.Task) bool {
+	return e.root.match(task)
+}
+
+// Filter условия запроса, которые хранилище может проверить по индексам:
+// сравнения на равенство индексируемых полей, объединенные по И на верхнем уровне.
+// Фильтр выбирает надмножество подходящих задач, окончательно их отбирает Match
+func (e *Expression) Filter() domain.TaskFilter {
+	return e.filter
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, syntaxError(tok.pos, "expected %s but found %s", kind, describe(tok))
+	}
+	return tok, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenIdent:
+		return p.parseComparison(tok)
+	default:
+		return nil, syntaxError(tok.pos, "expected field name or '(' but found %s", describe(tok))
+	}
+}
+
+func (p *parser) parseComparison(name token) (node, error) {
+	f, err := lookupField(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokenOperator:
+		if !f.ordered && tok.text != "=" && tok.text != "!=" {
+			return nil, syntaxError(tok.pos, "operator %s is not supported for field %s", tok.text, name.text)
+		}
+		value, err := p.parseValue(f, name.text)
+		if err != nil {
+			return nil, err
+		}
+		return comparison{field: f, op: tok.text, values: []operand{value}}, nil
+	case tokenNot:
+		if _, err := p.expect(tokenIn); err != nil {
+			return nil, err
+		}
+		values, err := p.parseList(f, name.text)
+		if err != nil {
+			return nil, err
+		}
+		return comparison{field: f, op: opNotIn, values: values}, nil
+	case tokenIn:
+		values, err := p.parseList(f, name.text)
+		if err != nil {
+			return nil, err
+		}
+		return comparison{field: f, op: opIn, values: values}, nil
+	default:
+		return nil, syntaxError(tok.pos, "expected operator after field %s but found %s", name.text, describe(tok))
+	}
+}
+
+func (p *parser) parseList(f field, name string) ([]operand, error) {
+	if _, err := p.expect(tokenLParen); err != nil {
+		return nil, err
+	}
+	var values []operand
+	for {
+		value, err := p.parseValue(f, name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			return values, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, syntaxError(tok.pos, "expected ',' or ')' but found %s", describe(tok))
+		}
+	}
+}
+
+func (p *parser) parseValue(f field, name string) (operand, error) {
+	tok := p.next()
+	if tok.kind != tokenString && tok.kind != tokenNumber {
+		return operand{}, syntaxError(tok.pos, "expected value but found %s", describe(tok))
+	}
+	value, err := f.literal(tok)
+	if err != nil {
+		return operand{}, syntaxError(tok.pos, "invalid value for field %s: %s", name, err)
+	}
+	return value, nil
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenEOF:
+		return tok.kind.String()
+	case tokenString, tokenNumber, tokenIdent:
+		return fmt.Sprintf("%s %s", tok.kind, tok.text)
+	default:
+		return fmt.Sprintf("'%s'", strings.ToUpper(tok.text))
+	}
+}
+
+// plan переносит в фильтр равенства индексируемых полей из конъюнкций верхнего уровня
+func plan(n node, filter *domain.TaskFilter) {
+	switch n := n.(type) {
+	case andNode:
+		plan(n.left, filter)
+		plan(n.right, filter)
+	case comparison:
+		if n.field.index == nil {
+			return
+		}
+		if n.op == "=" || (n.op == opIn && len(n.values) == 1) {
+			n.field.index(filter, n.values[0].str)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"svc-task_master/src/domain"
+	"testing"
+)
+
+func TestParseErrorPositions(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{``, 1, "query is empty"},
+		{`   `, 1, "query is empty"},
+		{`queue = "billing`, 9, "unterminated string"},
+		{`queue ! "x"`, 7, "unexpected '!'"},
+		{`priority = -`, 12, "expected number after '-'"},
+		{`queue = "a" # x`, 13, "unexpected character '#'"},
+		// Позиции считаются в символах, а не в байтах
+		{`metadata.имя = "ж" AND @`, 24, "unexpected character '@'"},
+		{`foo = "a"`, 1, "unknown field foo"},
+		{`queue = "a" AND bar = "b"`, 17, "unknown field bar"},
+		{`queue "a"`, 7, `expected operator after field queue but found string "a"`},
+		{`queue > "a"`, 7, "operator > is not supported for field queue"},
+		{`queue = `, 9, "expected value but found end of query"},
+		{`queue = ("a")`, 9, "expected value but found '('"},
+		{`retryCount = "2"`, 14, "invalid value for field retryCount: must be a number"},
+		{`retryCount = 1.2.3`, 14, "invalid value for field retryCount: malformed number"},
+		{`queue = 2`, 9, "invalid value for field queue: must be a string"},
+		{`status = "done"`, 10, "invalid value for field status"},
+		{`priority >= "urgent"`, 13, "invalid value for field priority"},
+		{`createdAt > "yesterday"`, 13, "invalid value for field createdAt"},
+		{`queue = "a" AND`, 16, "expected field name or '(' but found end of query"},
+		{`) OR queue = "a"`, 1, "expected field name or '(' but found ')'"},
+		{`NOT AND queue = "a"`, 5, "expected field name or '(' but found 'AND'"},
+		{`queue = "a" "b"`, 13, `unexpected string "b"`},
+		{`(queue = "a"`, 13, "expected ')' but found end of query"},
+		{`(queue = "a" OR type = "b")) AND id = "c"`, 28, "unexpected ')'"},
+		{`queue NOT "a"`, 11, `expected IN but found string "a"`},
+		{`queue IN "a"`, 10, `expected '(' but found string "a"`},
+		{`queue IN ("a" "b")`, 15, `expected ',' or ')' but found string "b"`},
+		{`queue IN ("a",)`, 15, "expected value but found ')'"},
+		{`queue IN ("a"`, 14, "expected ',' or ')' but found end of query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if !errors.Is(err, domain.ErrInvalidQuery) {
+				t.Fatalf("Parse error = %v, want ErrInvalidQuery", err)
+			}
+			var parseErr *Error
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse error = %T, want *Error", err)
+			}
+			if parseErr.Pos != tt.pos || !strings.Contains(parseErr.Msg, tt.msg) {
+				t.Fatalf("Parse error at %d: %q, want at %d: %q", parseErr.Pos, parseErr.Msg, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseAcceptsValidQueries(t *testing.T) {
+	for _, query := range []string{
+		`queue = "billing"`,
+		`queue = "billing" and priority in ("high", "critical") AND NOT (retryCount > 2 OR metadata.user_id = "123")`,
+		`status NOT IN ("completed", "cancelled")`,
+		`priority >= "high" OR retryCount <= -1`,
+		`queue <> "a" AND type != "b"`,
+		`metadata.name = "escaped \"quote\" and \\ slash"`,
+		`createdAt >= "2024-01-15T00:00:00Z"`,
+	} {
+		if _, err := Parse(query); err != nil {
+			t.Errorf("Parse(%s): %v", query, err)
+		}
+	}
+}
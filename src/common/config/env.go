@@ -7,9 +7,17 @@ import (
 )
 
 type Config struct {
-	Server   Server
-	Logger   Logger
-	MemoryDB MemoryDB
+	Server      Server
+	Logger      Logger
+	Storage     Storage
+	MemoryDB    MemoryDB
+	DiskDB      DiskDB
+	Retention   Retention
+	Archive     Archive
+	Idempotency Idempotency
+	Audit       Audit
+	Worker      Worker
+	Schedule    Schedule
 }
 
 type Logger struct {
@@ -17,9 +25,63 @@ type Logger struct {
 	BathSize int
 }
 
+type Storage struct {
+	// Driver адаптер хранилища задач: memory или disk
+	Driver string
+}
+
+type DiskDB struct {
+	Dir           string
+	Fsync         string
+	FsyncInterval time.Duration
+	MaxFileSize   int64
+	MergeInterval time.Duration
+}
+
+type Retention struct {
+	// Rules правила хранения задач в формате domain.ParseRetentionPolicy
+	Rules         string
+	CheckInterval time.Duration
+	// DeadLetterTTL время хранения задач в DLQ: длительность вида 720h или never
+	DeadLetterTTL string
+}
+
+// ArchiveDisabled значение ARCHIVE_DIR, при котором задачи удаляются без архивации
+const ArchiveDisabled = "off"
+
+type Archive struct {
+	// Dir каталог архива удаляемых задач или ArchiveDisabled
+	Dir           string
+	RetentionDays int
+}
+
+type Idempotency struct {
+	TTL time.Duration
+}
+
+type Audit struct {
+	// Dir каталог файла журнала аудита; пустое значение — журнал только в памяти
+	Dir string
+}
+
 type MemoryDB struct {
-	TTL       time.Duration
-	NumShards int
+	NumShards         int
+	PriorityAging     time.Duration
+	WALDir            string
+	WALFsync          string
+	WALFsyncInterval  time.Duration
+	SnapshotInterval  time.Duration
+	SnapshotRetention int
+}
+
+type Worker struct {
+	LeaseTTL           time.Duration
+	LeaseCheckInterval time.Duration
+}
+
+type Schedule struct {
+	CheckInterval    time.Duration
+	MisfireThreshold time.Duration
 }
 
 type Server struct {
@@ -35,9 +97,47 @@ func LoadConfig() *Config {
 			LogLvl:   parseEnvString("LOG_LEVEL", "debug"),
 			BathSize: parseEnvInt("BATCH_SIZE", 100),
 		},
+		Storage: Storage{
+			Driver: parseEnvString("STORAGE_DRIVER", "memory"),
+		},
+		DiskDB: DiskDB{
+			Dir:           parseEnvString("DISK_DIR", "data"),
+			Fsync:         parseEnvString("DISK_FSYNC", "interval"),
+			FsyncInterval: time.Duration(parseEnvInt("DISK_FSYNC_INTERVAL", 1000)) * time.Millisecond,
+			MaxFileSize:   int64(parseEnvInt("DISK_MAX_FILE_SIZE", 64)) << 20,
+			MergeInterval: time.Duration(parseEnvInt("DISK_MERGE_INTERVAL", 600)) * time.Second,
+		},
+		Retention: Retention{
+			Rules:         parseEnvString("RETENTION_RULES", "completed=1h,failed=168h,cancelled=1h"),
+			CheckInterval: time.Duration(parseEnvInt("RETENTION_CHECK_INTERVAL", 5)) * time.Second,
+			DeadLetterTTL: parseEnvString("DLQ_RETENTION", "720h"),
+		},
+		Archive: Archive{
+			Dir:           parseEnvString("ARCHIVE_DIR", "data/archive"),
+			RetentionDays: parseEnvInt("ARCHIVE_RETENTION_DAYS", 30),
+		},
+		Idempotency: Idempotency{
+			TTL: time.Duration(parseEnvInt("IDEMPOTENCY_TTL", 86400)) * time.Second,
+		},
+		Audit: Audit{
+			Dir: parseEnvString("AUDIT_DIR", ""),
+		},
 		MemoryDB: MemoryDB{
-			TTL:       time.Duration(parseEnvInt("MEMORY_TTL", 30)) * time.Second,
-			NumShards: parseEnvInt("NUM_SHARDS", 100),
+			NumShards:         parseEnvInt("NUM_SHARDS", 100),
+			PriorityAging:     time.Duration(parseEnvInt("PRIORITY_AGING", 0)) * time.Second,
+			WALDir:            parseEnvString("WAL_DIR", ""),
+			WALFsync:          parseEnvString("WAL_FSYNC", "interval"),
+			WALFsyncInterval:  time.Duration(parseEnvInt("WAL_FSYNC_INTERVAL", 1000)) * time.Millisecond,
+			SnapshotInterval:  time.Duration(parseEnvInt("SNAPSHOT_INTERVAL", 300)) * time.Second,
+			SnapshotRetention: parseEnvInt("SNAPSHOT_RETENTION", 2),
+		},
+		Worker: Worker{
+			LeaseTTL:           time.Duration(parseEnvInt("LEASE_TTL", 30)) * time.Second,
+			LeaseCheckInterval: time.Duration(parseEnvInt("LEASE_CHECK_INTERVAL", 5)) * time.Second,
+		},
+		Schedule: Schedule{
+			CheckInterval:    time.Duration(parseEnvInt("SCHEDULE_CHECK_INTERVAL", 5)) * time.Second,
+			MisfireThreshold: time.Duration(parseEnvInt("SCHEDULE_MISFIRE_THRESHOLD", 60)) * time.Second,
 		},
 	}
 }
@@ -0,0 +1,158 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"unknown descriptor", "@every-minute"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month below range", "0 0 0 * *"},
+		{"day of month above range", "0 0 32 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"unknown month name", "0 0 1 foo *"},
+		{"unknown weekday name", "0 0 * * mo"},
+		{"not a number", "x * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"negative step", "*/-5 * * * *"},
+		{"step is not a number", "*/x * * * *"},
+		{"reversed range", "0 18-8 * * *"},
+		{"range bound out of range", "0 20-25 * * *"},
+		{"empty list item", "0,,30 * * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.expr); err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", tt.expr)
+			}
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	bits := func(values ...int) uint64 {
+		var b uint64
+		for _, v := range values {
+			b |= 1 << uint(v)
+		}
+		return b
+	}
+	rangeBits := func(lo, hi, step int) uint64 {
+		var b uint64
+		for v := lo; v <= hi; v += step {
+			b |= 1 << uint(v)
+		}
+		return b
+	}
+
+	tests := []struct {
+		expr string
+		want Expression
+	}{
+		{"* * * * *", Expression{
+			minute: rangeBits(0, 59, 1), hour: rangeBits(0, 23, 1), dom: rangeBits(1, 31, 1),
+			month: rangeBits(1, 12, 1), dow: rangeBits(0, 7, 1), domAny: true, dowAny: true,
+		}},
+		{"*/15 8-18/5 1,15 jan-mar mon-fri", Expression{
+			minute: bits(0, 15, 30, 45), hour: bits(8, 13, 18), dom: bits(1, 15),
+			month: bits(1, 2, 3), dow: bits(1, 2, 3, 4, 5),
+		}},
+		// Шаг от одиночного значения идет до конца диапазона поля
+		{"5/20 0 ? * ?", Expression{
+			minute: bits(5, 25, 45), hour: bits(0), dom: rangeBits(1, 31, 1),
+			month: rangeBits(1, 12, 1), dow: rangeBits(0, 7, 1), domAny: true, dowAny: true,
+		}},
+		// 7 и 0 — воскресенье
+		{"0 0 * * 7", Expression{
+			minute: bits(0), hour: bits(0), dom: rangeBits(1, 31, 1),
+			month: rangeBits(1, 12, 1), dow: bits(0, 7), domAny: true,
+		}},
+		{"0 0 * * 5-7", Expression{
+			minute: bits(0), hour: bits(0), dom: rangeBits(1, 31, 1),
+			month: rangeBits(1, 12, 1), dow: bits(0, 5, 6, 7), domAny: true,
+		}},
+		{"@Weekly", Expression{
+			minute: bits(0), hour: bits(0), dom: rangeBits(1, 31, 1),
+			month: rangeBits(1, 12, 1), dow: bits(0), domAny: true,
+		}},
+		{"  0 12 * DEC SUN  ", Expression{
+			minute: bits(0), hour: bits(12), dom: rangeBits(1, 31, 1),
+			month: bits(12), dow: bits(0), domAny: true,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.expr, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute, sec int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
+	}
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// 1 января 2026 года — четверг
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", utc(2026, 1, 1, 10, 0, 0), utc(2026, 1, 1, 10, 1, 0)},
+		{"strictly after a matching minute", "0 10 * * *", utc(2026, 1, 1, 10, 0, 0), utc(2026, 1, 2, 10, 0, 0)},
+		{"seconds are truncated", "*/15 * * * *", utc(2026, 1, 1, 10, 14, 30), utc(2026, 1, 1, 10, 15, 0)},
+		{"minute step", "*/15 * * * *", utc(2026, 1, 1, 10, 7, 0), utc(2026, 1, 1, 10, 15, 0)},
+		{"minute list", "0,30 * * * *", utc(2026, 1, 1, 10, 0, 0), utc(2026, 1, 1, 10, 30, 0)},
+		{"hour range with step", "0 8-18/5 * * *", utc(2026, 1, 1, 9, 0, 0), utc(2026, 1, 1, 13, 0, 0)},
+		{"hour range wraps to the next day", "0 8-18/5 * * *", utc(2026, 1, 1, 18, 0, 0), utc(2026, 1, 2, 8, 0, 0)},
+		{"first day of next month", "0 0 1 * *", utc(2026, 1, 15, 0, 0, 0), utc(2026, 2, 1, 0, 0, 0)},
+		{"month name", "0 0 1 jun *", utc(2026, 1, 1, 0, 0, 0), utc(2026, 6, 1, 0, 0, 0)},
+		{"next year", "0 0 1 jan *", utc(2026, 1, 1, 0, 0, 0), utc(2027, 1, 1, 0, 0, 0)},
+		{"weekdays skip the weekend", "0 9 * * mon-fri", utc(2026, 1, 2, 9, 0, 0), utc(2026, 1, 5, 9, 0, 0)},
+		{"sunday as 0", "0 0 * * 0", utc(2026, 1, 1, 0, 0, 0), utc(2026, 1, 4, 0, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", utc(2026, 1, 1, 0, 0, 0), utc(2026, 1, 4, 0, 0, 0)},
+		{"day of week with restricted month", "0 0 * feb mon", utc(2026, 1, 1, 0, 0, 0), utc(2026, 2, 2, 0, 0, 0)},
+		{"day of month or day of week: weekday first", "0 0 13 * fri", utc(2026, 1, 1, 0, 0, 0), utc(2026, 1, 2, 0, 0, 0)},
+		{"day of month or day of week: next weekday", "0 0 13 * fri", utc(2026, 1, 2, 0, 0, 0), utc(2026, 1, 9, 0, 0, 0)},
+		{"day of month or day of week: day of month", "0 0 13 * fri", utc(2026, 1, 9, 0, 0, 0), utc(2026, 1, 13, 0, 0, 0)},
+		{"day of month with any weekday", "0 0 13 * ?", utc(2026, 1, 1, 0, 0, 0), utc(2026, 1, 13, 0, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", utc(2026, 4, 1, 0, 0, 0), utc(2026, 5, 31, 0, 0, 0)},
+		{"leap day", "30 2 29 2 *", utc(2026, 1, 1, 0, 0, 0), utc(2028, 2, 29, 2, 30, 0)},
+		{"impossible date", "0 0 30 2 *", utc(2026, 1, 1, 0, 0, 0), time.Time{}},
+		{"descriptor", "@hourly", utc(2026, 1, 1, 10, 30, 0), utc(2026, 1, 1, 11, 0, 0)},
+		{"time zone of after", "0 9 * * *", time.Date(2026, 1, 1, 10, 0, 0, 0, moscow), time.Date(2026, 1, 2, 9, 0, 0, 0, moscow)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got := expr.Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Fatalf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != tt.after.Location() {
+				t.Fatalf("Next(%s) is in %s, want %s", tt.after, got.Location(), tt.after.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package decorator
+
+import (
+	"context"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+// auditTarget команда над конкретной задачей
+type auditTarget interface {
+	TaskID() string
+}
+
+// CommandAuditDecorator записывает в журнал аудита каждое выполнение команды:
+// кто и когда ее выполнил и как изменился статус задачи, а также отдельной записью —
+// каждую связанную задачу, которую команда изменила (каскадная отмена, ошибка
+// зависимости, агрегат родителя, массовые операции DLQ, истечение аренды)
+type CommandAuditDecorator[C any, R any] struct {
+	base   CommandHandlerDecorator[C, R]
+	name   string
+	audit  domain.IAuditRepository
+	tasks  domain.IInMemoRepository
+	logger domain.ILogger
+}
+
+func ApplyCommandAuditDecorator[C any, R any](
+	handler CommandHandlerDecorator[C, R],
+	name string,
+	audit domain.IAuditRepository,
+	tasks domain.IInMemoRepository,
+	logger domain.ILogger,
+) CommandHandlerDecorator[C, R] {
+	return CommandAuditDecorator[C, R]{
+		base:   handler,
+		name:   name,
+		audit:  audit,
+		tasks:  tasks,
+		logger: logger,
+	}
+}
+
+func (d CommandAuditDecorator[C, R]) Handle(ctx context.Context, cmd C) (R, error) {
+	actor, claimed, requestID := domain.AuditFromContext(ctx)
+	entry := domain.AuditEntry{
+		Actor:        actor,
+		ActorClaimed: claimed,
+		Command:      d.name,
+		RequestID:    requestID,
+	}
+	if target, ok := any(cmd).(auditTarget); ok {
+		entry.TaskID = target.TaskID()
+		if task, ok := d.tasks.Get(entry.TaskID); ok {
+			entry.StatusBefore = task.Status
+		}
+	}
+
+	ctx, changes := domain.WithAuditChanges(ctx)
+	result, err := d.base.Handle(ctx, cmd)
+	entry.Time = time.Now()
+
+	if d.complete(&entry, result, err) {
+		d.append(entry)
+	}
+	for _, change := range changes() {
+		change.Time = entry.Time
+		change.Actor = actor
+		change.ActorClaimed = claimed
+		change.Command = d.name
+		change.RequestID = requestID
+		d.append(change)
+	}
+	return result, err
+}
+
+// complete дополняет запись о команде результатом ее выполнения и сообщает,
+// нужно ли записывать ее в журнал
+func (d CommandAuditDecorator[C, R]) complete(entry *domain.AuditEntry, result R, err error) bool {
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	switch res := any(result).(type) {
+	case domain.Task:
+		if err == nil {
+			entry.TaskID = res.ID
+			entry.StatusAfter = res.Status
+		}
+	case domain.CreatedTask:
+		// Повтор запроса с ключом идемпотентности задачу не создает
+		if res.Replayed {
+			return false
+		}
+		if entry.TaskID == "" {
+			entry.TaskID = res.ID
+		}
+	}
+	// Неудачная команда без задачи (например, пустая очередь при claim) ничего не изменила,
+	// а фоновая команда без задачи записывает только изменения отдельных задач
+	if entry.TaskID == "" && (err != nil || entry.RequestID == "") {
+		return false
+	}
+	if entry.TaskID != "" && entry.StatusAfter == "" {
+		if task, ok := d.tasks.Get(entry.TaskID); ok {
+			entry.StatusAfter = task.Status
+		}
+	}
+	return true
+}
+
+func (d CommandAuditDecorator[C, R]) append(entry domain.AuditEntry) {
+	if _, err := d.audit.Append(entry); err != nil {
+		d.logger.Error("Failed to write audit entry",
+			slog.String("command", entry.Command),
+			slog.String("task_id", entry.TaskID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
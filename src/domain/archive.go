@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ArchivedTask задача, удаленная из хранилища по правилам хранения
+// swagger:model ArchivedTask
+type ArchivedTask struct {
+	Task Task `json:"task"`
+
+	// Время архивации задачи
+	// example: "2024-01-15T10:00:00Z"
+	ArchivedAt time.Time `json:"archivedAt"`
+}
+
+// ITaskArchive архив задач, удаляемых из хранилища по правилам хранения
+type ITaskArchive interface {
+	Archive(tasks []Task, at time.Time) error
+	// Get возвращает последнюю архивную копию задачи
+	Get(ctx context.Context, id string) (ArchivedTask, error)
+	// Find возвращает архивные задачи, последнее изменение которых попадает в [from, to],
+	// в порядке изменения; нулевые границы не ограничивают выборку
+	Find(ctx context.Context, from, to time.Time, limit int) ([]ArchivedTask, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// MaxTaskAttempts сколько последних попыток хранится в истории задачи
+const MaxTaskAttempts = 20
+
+// TaskAttempt завершенная попытка выполнения задачи воркером
+// swagger:model TaskAttempt
+type TaskAttempt struct {
+	// Порядковый номер попытки, считая с 1
+	// example: 2
+	Number int `json:"number"`
+
+	// ID воркера, выполнявшего попытку
+	// example: "worker-1"
+	WorkerID string `json:"workerId,omitempty"`
+
+	// Время начала попытки
+	// example: "2024-01-15T10:00:00Z"
+	StartedAt time.Time `json:"startedAt"`
+
+	// Время завершения попытки
+	// example: "2024-01-15T10:00:05Z"
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// Статус, в который задача перешла по итогам попытки
+	// enum: completed,failed,retrying,cancelled
+	// example: "retrying"
+	Outcome TaskStatus `json:"outcome"`
+
+	// Код ошибки попытки
+	// example: "LEASE_EXPIRED"
+	ErrorCode string `json:"errorCode,omitempty"`
+
+	// Сообщение об ошибке попытки
+	// example: "worker lease expired"
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// Длительность попытки в миллисекундах
+	// example: 5000
+	DurationMs int64 `json:"durationMs"`
+}
+
+// finishAttempt добавляет в историю попытку, которая завершилась переходом задачи
+// из processing в статус outcome. Хранятся только MaxTaskAttempts последних попыток
+func (t *Task) finishAttempt(outcome TaskStatus, taskErr *TaskError, now time.Time) {
+	attempt := TaskAttempt{
+		Number:     1,
+		WorkerID:   t.WorkerID,
+		FinishedAt: now,
+		Outcome:    outcome,
+	}
+	if n := len(t.Attempts); n > 0 {
+		attempt.Number = t.Attempts[n-1].Number + 1
+	}
+	if t.StartedAt != nil {
+		attempt.StartedAt = *t.StartedAt
+		attempt.DurationMs = now.Sub(*t.StartedAt).Milliseconds()
+	}
+	if taskErr != nil {
+		attempt.ErrorCode = taskErr.Code
+		attempt.ErrorMessage = taskErr.Message
+	}
+
+	// Историю всегда копируем: срез может разделяться с версией задачи, уже лежащей в хранилище
+	keep := t.Attempts
+	if len(keep) >= MaxTaskAttempts {
+		keep = keep[len(keep)-MaxTaskAttempts+1:]
+	}
+	attempts := make([]TaskAttempt, 0, len(keep)+1)
+	attempts = append(attempts, keep...)
+	t.Attempts = append(attempts, attempt)
+}
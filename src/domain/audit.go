@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AuditActorSystem инициатор изменений, которые выполняет сам сервис, а не клиент API
+const AuditActorSystem = "system"
+
+// AuditEntry запись журнала аудита о выполнении команды
+// swagger:model AuditEntry
+type AuditEntry struct {
+	// Порядковый номер записи в журнале
+	// example: 42
+	Seq int64 `json:"seq"`
+
+	// Время выполнения команды
+	// example: "2024-01-15T10:00:00Z"
+	Time time.Time `json:"time"`
+
+	// Инициатор изменения. Для запросов API — значение заголовка X-Actor, которое
+	// сервис не проверяет (см. actorClaimed)
+	// example: "billing-service"
+	Actor string `json:"actor"`
+
+	// Инициатор заявлен клиентом в заголовке X-Actor и не подтвержден аутентификацией
+	// example: true
+	ActorClaimed bool `json:"actorClaimed,omitempty"`
+
+	// Имя команды
+	// example: "UpdateTask"
+	Command string `json:"command"`
+
+	// ID задачи, над которой выполнена команда
+	// example: "550e8400-e29b-41d4-a716-446655440000"
+	TaskID string `json:"taskId,omitempty"`
+
+	// Статус задачи до выполнения команды
+	// example: "processing"
+	StatusBefore TaskStatus `json:"statusBefore,omitempty"`
+
+	// Статус задачи после выполнения команды
+	// example: "completed"
+	StatusAfter TaskStatus `json:"statusAfter,omitempty"`
+
+	// ID задачи, изменение которой повлекло изменение этой задачи
+	// (например, отмена или ошибка зависимости); пусто для основной задачи команды
+	// example: "task-456"
+	CausedBy string `json:"causedBy,omitempty"`
+
+	// ID HTTP-запроса, в рамках которого выполнена команда
+	// example: "7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b"
+	RequestID string `json:"requestId,omitempty"`
+
+	// Ошибка, с которой завершилась команда
+	// example: "invalid status transition"
+	Error string `json:"error,omitempty"`
+}
+
+// AuditFilter условия выборки записей аудита. Пустые поля не ограничивают выборку,
+// границы времени включительны
+type AuditFilter struct {
+	TaskID string
+	Actor  string
+	From   time.Time
+	To     time.Time
+	// Limit максимальное количество записей; 0 — без ограничения
+	Limit int
+}
+
+func (f AuditFilter) Match(entry AuditEntry) bool {
+	switch {
+	case f.TaskID != "" && entry.TaskID != f.TaskID,
+		f.Actor != "" && entry.Actor != f.Actor:
+		return false
+	}
+	return inTimeRange(entry.Time, f.From, f.To)
+}
+
+// IAuditRepository журнал аудита, в который записи только добавляются
+type IAuditRepository interface {
+	// Append присваивает записи порядковый номер и сохраняет ее
+	Append(entry AuditEntry) (AuditEntry, error)
+	// Find возвращает подходящие записи в порядке добавления
+	Find(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+type auditContextKey struct{}
+
+type auditContext struct {
+	actor     string
+	claimed   bool
+	requestID string
+}
+
+// WithAudit сохраняет в контексте инициатора изменения и ID запроса для журнала аудита;
+// claimed — инициатор указан клиентом и не проверен
+func WithAudit(ctx context.Context, actor string, claimed bool, requestID string) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditContext{actor: actor, claimed: claimed, requestID: requestID})
+}
+
+// AuditFromContext возвращает инициатора изменения, признак того, что он только заявлен
+// клиентом, и ID запроса; без них инициатором считается сам сервис
+func AuditFromContext(ctx context.Context) (actor string, claimed bool, requestID string) {
+	audit, _ := ctx.Value(auditContextKey{}).(auditContext)
+	if audit.actor == "" {
+		audit.actor = AuditActorSystem
+	}
+	return audit.actor, audit.claimed, audit.requestID
+}
+
+type auditChangesKey struct{}
+
+type auditChanges struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// WithAuditChanges подготавливает контекст к сбору изменений задач, сделанных командой
+// помимо ее основной задачи; changes возвращает собранные изменения
+func WithAuditChanges(ctx context.Context) (_ context.Context, changes func() []AuditEntry) {
+	collected := &auditChanges{}
+	changes = func() []AuditEntry {
+		collected.mu.Lock()
+		defer collected.mu.Unlock()
+		return collected.entries
+	}
+	return context.WithValue(ctx, auditChangesKey{}, collected), changes
+}
+
+// RecordAuditChange отмечает изменение задачи для журнала аудита выполняемой команды.
+// Заполняются только TaskID, статусы и CausedBy; вне команды вызов ничего не делает
+func RecordAuditChange(ctx context.Context, change AuditEntry) {
+	collected, ok := ctx.Value(auditChangesKey{}).(*auditChanges)
+	if !ok {
+		return
+	}
+	collected.mu.Lock()
+	collected.entries = append(collected.entries, change)
+	collected.mu.Unlock()
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Cancel отменяет задачу. Невыполняющаяся задача сразу переходит в cancelled;
+// у выполняющейся только выставляется CancelRequested, а в cancelled ее переводит
+// воркер, узнавший об отмене из heartbeat. Возвращает true, если задача отменена сразу
+func (t *Task) Cancel(now time.Time) (bool, error) {
+	if t.Status == TaskStatusProcessing {
+		t.CancelRequested = true
+		t.UpdatedAt = now
+		return false, nil
+	}
+	if err := t.TransitionTo(TaskStatusCancelled, now); err != nil {
+		return false, err
+	}
+	return true, nil
+}
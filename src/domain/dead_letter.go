@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+const (
+	// DeadLetterReasonRetriesExhausted попытки выполнения исчерпаны
+	DeadLetterReasonRetriesExhausted = "RETRIES_EXHAUSTED"
+	// DeadLetterReasonFinalError воркер сообщил об ошибке, повтор после которой не поможет
+	DeadLetterReasonFinalError = "FINAL_ERROR"
+)
+
+// DeadLetter отметка о переносе задачи в очередь недоставленных задач (DLQ) своей очереди.
+// Контекст ошибки остается в задаче: lastError, retryCount и история попыток
+// swagger:model DeadLetter
+type DeadLetter struct {
+	// Время переноса в DLQ
+	// example: "2024-01-15T10:05:00Z"
+	At time.Time `json:"at"`
+
+	// Причина переноса
+	// enum: RETRIES_EXHAUSTED,FINAL_ERROR
+	// example: "RETRIES_EXHAUSTED"
+	Reason string `json:"reason"`
+}
+
+// InDeadLetterQueue сообщает, что задача находится в DLQ очереди queue
+func (t Task) InDeadLetterQueue(queue string) bool {
+	return t.DeadLetter != nil && t.Status == TaskStatusFailed && t.Queue == queue
+}
+
+func (t *Task) deadLetter(reason string, now time.Time) {
+	t.DeadLetter = &DeadLetter{At: now, Reason: reason}
+}
+
+// Requeue возвращает задачу из DLQ в очередь: задача снова ожидает выполнения
+// с полным набором попыток. lastError и история попыток сохраняются
+func (t *Task) Requeue(queue string, now time.Time) error {
+	if !t.InDeadLetterQueue(queue) {
+		return ErrDeadLetterNotFound
+	}
+	if err := t.TransitionTo(TaskStatusPending, now); err != nil {
+		return err
+	}
+	t.RetryCount = 0
+	t.ScheduledAt = nil
+	t.StartedAt = nil
+	t.DeadLetter = nil
+	return nil
+}
+
+// DeadLetterExpired сообщает, что задача пробыла в DLQ дольше ttl; ttl = 0 — бессрочно
+func (t Task) DeadLetterExpired(ttl RetentionTTL, now time.Time) bool {
+	if t.DeadLetter == nil || ttl <= 0 {
+		return false
+	}
+	return t.DeadLetter.At.Add(time.Duration(ttl)).Before(now)
+}
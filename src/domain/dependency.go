@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ErrorCodeDependencyFailed    = "DEPENDENCY_FAILED"
+	ErrorCodeDependencyCancelled = "DEPENDENCY_CANCELLED"
+)
+
+func (t Task) DependsOnTask(id string) bool {
+	for _, dep := range t.DependsOn {
+		if dep == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Unblock делает задачу доступной после выполнения всех зависимостей
+func (t *Task) Unblock(now time.Time) error {
+	next := TaskStatusPending
+	if !t.IsDue(now) {
+		next = TaskStatusScheduled
+	}
+	if t.Status != TaskStatusBlocked {
+		return &TransitionError{From: t.Status, To: next}
+	}
+	return t.TransitionTo(next, now)
+}
+
+// FailDependency завершает заблокированную задачу, зависимость которой
+// окончательно завершилась с ошибкой или была отменена
+func (t *Task) FailDependency(dependency Task, now time.Time) error {
+	if t.Status != TaskStatusBlocked {
+		return &TransitionError{From: t.Status, To: TaskStatusFailed}
+	}
+	if err := t.TransitionTo(TaskStatusFailed, now); err != nil {
+		return err
+	}
+	t.LastError = &TaskError{
+		Message: fmt.Sprintf("dependency %s failed", dependency.ID),
+		Code:    ErrorCodeDependencyFailed,
+	}
+	if dependency.Status == TaskStatusCancelled {
+		t.LastError = &TaskError{
+			Message: fmt.Sprintf("dependency %s was cancelled", dependency.ID),
+			Code:    ErrorCodeDependencyCancelled,
+		}
+	}
+	return nil
+}
+
+// FailedByDependency сообщает, что задача завершилась из-за ошибки одной из зависимостей
+func (t Task) FailedByDependency() bool {
+	return t.Status == TaskStatusFailed && t.LastError != nil && t.LastError.Code == ErrorCodeDependencyFailed
+}
+
+// RestoreDependency возвращает в blocked задачу, завершенную из-за ошибки
+// зависимости, когда эту зависимость вернули из DLQ в очередь
+func (t *Task) RestoreDependency(now time.Time) error {
+	if !t.FailedByDependency() {
+		return &TransitionError{From: t.Status, To: TaskStatusBlocked}
+	}
+	if err := t.TransitionTo(TaskStatusBlocked, now); err != nil {
+		return err
+	}
+	t.LastError = nil
+	return nil
+}
@@ -5,13 +5,25 @@ import "time"
 type TaskStatus string
 
 const (
+	TaskStatusBlocked    TaskStatus = "blocked"
+	TaskStatusScheduled  TaskStatus = "scheduled"
 	TaskStatusPending    TaskStatus = "pending"
 	TaskStatusProcessing TaskStatus = "processing"
 	TaskStatusCompleted  TaskStatus = "completed"
 	TaskStatusFailed     TaskStatus = "failed"
 	TaskStatusRetrying   TaskStatus = "retrying"
+	TaskStatusCancelled  TaskStatus = "cancelled"
 )
 
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case TaskStatusBlocked, TaskStatusScheduled, TaskStatusPending, TaskStatusProcessing,
+		TaskStatusCompleted, TaskStatusFailed, TaskStatusRetrying, TaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
 type TaskPriority string
 
 const (
@@ -21,6 +33,19 @@ const (
 	TaskPriorityCritical TaskPriority = "critical"
 )
 
+func (p TaskPriority) Weight() int {
+	switch p {
+	case TaskPriorityCritical:
+		return 3
+	case TaskPriorityHigh:
+		return 2
+	case TaskPriorityMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // Task представляет задачу в системе
 // swagger:model Task
 type Task struct {
@@ -33,7 +58,7 @@ type Task struct {
 	Type string `json:"type"`
 
 	// Текущий статус задачи
-	// enum: pending,processing,completed,failed,retrying
+	// enum: blocked,scheduled,pending,processing,completed,failed,retrying,cancelled
 	// example: "pending"
 	Status TaskStatus `json:"status"`
 
@@ -78,6 +103,9 @@ type Task struct {
 	// example: 3
 	MaxRetries int `json:"maxRetries"`
 
+	// Политика задержки между повторами
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
 	// Информация о последней ошибке
 	LastError *TaskError `json:"lastError,omitempty"`
 
@@ -85,6 +113,9 @@ type Task struct {
 	// example: "task-123"
 	ParentTaskID string `json:"parentTaskId,omitempty"`
 
+	// Сводное состояние дочерних задач
+	Children *TaskAggregate `json:"children,omitempty"`
+
 	// Список зависимостей
 	// example: ["task-456", "task-789"]
 	DependsOn []string `json:"dependsOn,omitempty"`
@@ -97,8 +128,27 @@ type Task struct {
 	// example: "worker-1"
 	WorkerID string `json:"workerId,omitempty"`
 
+	// Время истечения аренды задачи воркером
+	// example: "2024-01-15T10:00:30Z"
+	LeaseExpiresAt *time.Time `json:"leaseExpiresAt,omitempty"`
+
+	// Запрошена отмена выполняющейся задачи; воркер узнает об этом из ответа на heartbeat
+	// и должен прекратить выполнение, переведя задачу в cancelled
+	// example: false
+	CancelRequested bool `json:"cancelRequested,omitempty"`
+
 	// Результат выполнения задачи
 	Output interface{} `json:"output,omitempty"`
+
+	// История завершенных попыток выполнения, не более MaxTaskAttempts последних
+	Attempts []TaskAttempt `json:"attempts,omitempty"`
+
+	// Отметка о переносе в DLQ очереди после окончательной ошибки выполнения
+	DeadLetter *DeadLetter `json:"deadLetter,omitempty"`
+
+	// Версия задачи; увеличивается хранилищем при каждой записи
+	// example: 3
+	Version int64 `json:"version"`
 }
 
 // TaskError представляет информацию об ошибке задачи
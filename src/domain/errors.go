@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTaskNotFound            = errors.New("task not found")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrNoTaskAvailable         = errors.New("no task available in queue")
+	ErrLeaseNotHeld            = errors.New("task lease is not held by worker")
+	ErrScheduleNotFound        = errors.New("schedule not found")
+	ErrDependencyNotFound      = errors.New("dependency not found")
+	ErrDependencyFailed        = errors.New("dependency has failed or was cancelled")
+	ErrParentTaskNotFound      = errors.New("parent task not found")
+	ErrArchivedTaskNotFound    = errors.New("archived task not found")
+	ErrArchiveDisabled         = errors.New("task archive is disabled")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrInvalidQuery            = errors.New("invalid query")
+	ErrVersionConflict         = errors.New("task version mismatch")
+	ErrDeadLetterNotFound      = errors.New("task not found in dead-letter queue")
+)
+
+// TransitionError описывает отклоненный переход задачи между статусами
+type TransitionError struct {
+	From TaskStatus
+	To   TaskStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// VersionConflictError описывает отклоненную запись задачи, версия которой
+// изменилась с момента чтения
+type VersionConflictError struct {
+	Expected int64
+	Actual   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: expected %d, current %d", ErrVersionConflict, e.Expected, e.Actual)
+}
+
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// CreatedTask результат создания задачи. Replayed сообщает, что задача с тем же
+// ключом идемпотентности уже была создана и возвращен ID исходной задачи
+type CreatedTask struct {
+	ID       string
+	Replayed bool
+}
+
+type IIdempotencyRepository interface {
+	// Reserve закрепляет ключ за задачей taskID. Если ключ уже закреплен и его срок
+	// не истек, возвращает ID исходной задачи и true
+	Reserve(key string, taskID string, now time.Time) (string, bool, error)
+	// Release снимает ключ, если он закреплен за taskID, например когда задачу не удалось создать
+	Release(key string, taskID string)
+}
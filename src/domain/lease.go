@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+const ErrorCodeLeaseExpired = "LEASE_EXPIRED"
+
+func (t Task) IsClaimable(now time.Time) bool {
+	switch t.Status {
+	case TaskStatusPending:
+		return true
+	case TaskStatusRetrying:
+		return t.IsDue(now)
+	default:
+		return false
+	}
+}
+
+// IsDue сообщает, наступило ли запланированное время выполнения задачи
+func (t Task) IsDue(now time.Time) bool {
+	return t.ScheduledAt == nil || !t.ScheduledAt.After(now)
+}
+
+// ClaimPrecedes сообщает, должна ли задача быть выдана воркеру раньше other
+func (t Task) ClaimPrecedes(other Task) bool {
+	if t.Priority.Weight() != other.Priority.Weight() {
+		return t.Priority.Weight() > other.Priority.Weight()
+	}
+	if !t.CreatedAt.Equal(other.CreatedAt) {
+		return t.CreatedAt.Before(other.CreatedAt)
+	}
+	return t.ID < other.ID
+}
+
+// Claim передает задачу воркеру в аренду на время lease
+func (t *Task) Claim(workerID string, lease time.Duration, now time.Time) error {
+	if err := t.TransitionTo(TaskStatusProcessing, now); err != nil {
+		return err
+	}
+	expiresAt := now.Add(lease)
+	t.WorkerID = workerID
+	t.LeaseExpiresAt = &expiresAt
+	return nil
+}
+
+// ExtendLease продлевает аренду задачи воркером, который ее удерживает
+func (t *Task) ExtendLease(workerID string, lease time.Duration, now time.Time) error {
+	if !t.HeldBy(workerID) {
+		return ErrLeaseNotHeld
+	}
+	expiresAt := now.Add(lease)
+	t.LeaseExpiresAt = &expiresAt
+	t.UpdatedAt = now
+	return nil
+}
+
+func (t Task) LeaseExpired(now time.Time) bool {
+	return t.Status == TaskStatusProcessing && t.LeaseExpiresAt != nil && t.LeaseExpiresAt.Before(now)
+}
+
+// ExpireLease снимает задачу с воркера, аренда которого истекла,
+// и возвращает ее на повтор либо завершает с ошибкой, если попытки исчерпаны
+func (t *Task) ExpireLease(now time.Time) error {
+	if !t.LeaseExpired(now) {
+		return ErrLeaseNotHeld
+	}
+	return t.registerFailure(&TaskError{
+		Message: "worker lease expired",
+		Code:    ErrorCodeLeaseExpired,
+	}, now)
+}
+
+func (t *Task) releaseLease() {
+	t.WorkerID = ""
+	t.LeaseExpiresAt = nil
+}
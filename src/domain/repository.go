@@ -1,10 +1,23 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type IInMemoRepository interface {
 	Get(key string) (Task, bool)
-	SetUpdate(key string, data Task)
+	SetUpdate(key string, data Task) error
 	GetAllFilterStatus(ctx context.Context, status TaskStatus) ([]Task, error)
-	UpdateStatus(key string, status TaskStatus)
+	ListTasks(ctx context.Context, query TaskQuery) (TaskPage, error)
+	UpdateStatus(key string, status TaskStatus) error
+	Update(key string, fn func(task *Task) error) (Task, error)
+	// CompareAndSwap применяет fn, только если версия задачи равна expected,
+	// иначе возвращает ErrVersionConflict
+	CompareAndSwap(key string, expected int64, fn func(task *Task) error) (Task, error)
+	// Delete удаляет задачу, если fn не вернула ошибку, и возвращает удаленную задачу
+	Delete(key string, fn func(task *Task) error) (Task, error)
+	ClaimNext(ctx context.Context, queue string, workerID string, lease time.Duration) (Task, error)
+	GetDependents(ctx context.Context, key string) ([]Task, error)
+	GetChildren(ctx context.Context, key string) ([]Task, error)
 }
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Complete завершает задачу по отчету воркера, удерживающего аренду, и сохраняет результат
+func (t *Task) Complete(workerID string, output interface{}, now time.Time) error {
+	if !t.HeldBy(workerID) {
+		return ErrLeaseNotHeld
+	}
+	if err := t.TransitionTo(TaskStatusCompleted, now); err != nil {
+		return err
+	}
+	t.Output = output
+	return nil
+}
+
+// FailAttempt фиксирует ошибку попытки по отчету воркера, удерживающего аренду.
+// Задача уходит на повтор по политике повторов, если попытки не исчерпаны
+// и ошибка не окончательная (final), иначе переходит в failed и переносится в DLQ
+func (t *Task) FailAttempt(workerID string, taskErr *TaskError, final bool, now time.Time) error {
+	if !t.HeldBy(workerID) {
+		return ErrLeaseNotHeld
+	}
+	if final && !t.CancelRequested {
+		if err := t.transition(TaskStatusFailed, taskErr, now); err != nil {
+			return err
+		}
+		if taskErr != nil {
+			t.LastError = taskErr
+		}
+		t.releaseLease()
+		t.deadLetter(DeadLetterReasonFinalError, now)
+		return nil
+	}
+	return t.registerFailure(taskErr, now)
+}
+
+// HeldBy сообщает, что задача выполняется воркером workerID
+func (t Task) HeldBy(workerID string) bool {
+	return t.Status == TaskStatusProcessing && t.WorkerID == workerID
+}
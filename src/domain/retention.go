@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const retentionNever = "never"
+
+// RetentionTTL время хранения задачи после последнего изменения; 0 — задача не удаляется.
+// В JSON представляется строкой длительности ("1h30m") или "never"
+type RetentionTTL time.Duration
+
+func ParseRetentionTTL(value string) (RetentionTTL, error) {
+	if value == retentionNever {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retention ttl: %s, must be a duration like 1h30m or never", value)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid retention ttl: %s, must be positive, use never to keep tasks", value)
+	}
+	return RetentionTTL(ttl), nil
+}
+
+func (t RetentionTTL) String() string {
+	if t <= 0 {
+		return retentionNever
+	}
+	return time.Duration(t).String()
+}
+
+func (t RetentionTTL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *RetentionTTL) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid retention ttl: %s, must be a string", data)
+	}
+	ttl, err := ParseRetentionTTL(value)
+	if err != nil {
+		return err
+	}
+	*t = ttl
+	return nil
+}
+
+// RetentionRule правило хранения задач. Пустые Status или Queue означают любое значение
+// swagger:model RetentionRule
+type RetentionRule struct {
+	// Статус задач, к которым применяется правило
+	// example: "completed"
+	Status TaskStatus `json:"status,omitempty"`
+
+	// Очередь задач, к которым применяется правило
+	// example: "reports"
+	Queue string `json:"queue,omitempty"`
+
+	// Время хранения после последнего изменения задачи или never
+	// example: "1h"
+	TTL RetentionTTL `json:"ttl" swaggertype:"string"`
+}
+
+func (r RetentionRule) matches(task *Task) bool {
+	return (r.Status == "" || r.Status == task.Status) && (r.Queue == "" || r.Queue == task.Queue)
+}
+
+// specificity: правило для очереди и статуса точнее правила только для очереди,
+// а оно — точнее правила только для статуса
+func (r RetentionRule) specificity() int {
+	score := 0
+	if r.Queue != "" {
+		score += 2
+	}
+	if r.Status != "" {
+		score++
+	}
+	return score
+}
+
+func (r RetentionRule) String() string {
+	status := string(r.Status)
+	if status == "" {
+		status = "*"
+	}
+	if r.Queue != "" {
+		status += "@" + r.Queue
+	}
+	return status + "=" + r.TTL.String()
+}
+
+// RetentionPolicy набор правил хранения задач. К задаче применяется самое точное
+// подходящее правило; задачи, для которых правила нет, не удаляются
+type RetentionPolicy struct {
+	Rules []RetentionRule `json:"rules"`
+}
+
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{Rules: []RetentionRule{
+		{Status: TaskStatusCompleted, TTL: RetentionTTL(time.Hour)},
+		{Status: TaskStatusFailed, TTL: RetentionTTL(7 * 24 * time.Hour)},
+		{Status: TaskStatusCancelled, TTL: RetentionTTL(time.Hour)},
+	}}
+}
+
+// TTLFor возвращает время хранения задачи; 0 — задача не удаляется
+func (p RetentionPolicy) TTLFor(task *Task) RetentionTTL {
+	var (
+		best  *RetentionRule
+		score = -1
+	)
+	for i, rule := range p.Rules {
+		if rule.matches(task) && rule.specificity() > score {
+			best, score = &p.Rules[i], rule.specificity()
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return best.TTL
+}
+
+// Expired сообщает, что время хранения задачи истекло
+func (p RetentionPolicy) Expired(task *Task, now time.Time) bool {
+	ttl := p.TTLFor(task)
+	if ttl <= 0 {
+		return false
+	}
+	return task.UpdatedAt.Add(time.Duration(ttl)).Before(now)
+}
+
+func (p RetentionPolicy) Validate() error {
+	seen := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.Status != "" && !rule.Status.IsValid() {
+			return fmt.Errorf("invalid retention rule status: %s", rule.Status)
+		}
+		key := string(rule.Status) + "@" + rule.Queue
+		if seen[key] {
+			return fmt.Errorf("duplicate retention rule: %s", rule)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ParseRetentionPolicy разбирает правила вида "completed=1h,failed@emails=168h,*@tmp=10m,pending=never",
+// где до "=" указывается статус ("*" — любой) и, через "@", очередь
+func ParseRetentionPolicy(spec string) (RetentionPolicy, error) {
+	policy := RetentionPolicy{Rules: []RetentionRule{}}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		selector, ttlValue, ok := strings.Cut(part, "=")
+		if !ok {
+			return RetentionPolicy{}, fmt.Errorf("invalid retention rule: %s, expected status[@queue]=ttl", part)
+		}
+		status, queue, _ := strings.Cut(strings.TrimSpace(selector), "@")
+		if status == "*" {
+			status = ""
+		}
+		ttl, err := ParseRetentionTTL(strings.TrimSpace(ttlValue))
+		if err != nil {
+			return RetentionPolicy{}, err
+		}
+		policy.Rules = append(policy.Rules, RetentionRule{Status: TaskStatus(status), Queue: queue, TTL: ttl})
+	}
+	if err := policy.Validate(); err != nil {
+		return RetentionPolicy{}, err
+	}
+	return policy, nil
+}
+
+// EvictionStats количество задач, удаленных по правилам хранения
+// swagger:model EvictionStats
+type EvictionStats struct {
+	// Всего удалено задач
+	// example: 42
+	Total int64 `json:"total"`
+
+	// Удалено задач по статусам
+	ByStatus map[TaskStatus]int64 `json:"byStatus"`
+
+	// Удалено задач по очередям
+	ByQueue map[string]int64 `json:"byQueue"`
+
+	// Время последней проверки
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+}
+
+// Retention текущие правила хранения и статистика удалений
+// swagger:model Retention
+type Retention struct {
+	Policy    RetentionPolicy `json:"policy"`
+	Evictions EvictionStats   `json:"evictions"`
+
+	// Время хранения задач в DLQ с момента переноса или never; правила policy к ним не применяются
+	// example: "720h0m0s"
+	DeadLetterTTL RetentionTTL `json:"deadLetterTtl" swaggertype:"string"`
+}
+
+type IRetentionRepository interface {
+	GetRetentionPolicy() RetentionPolicy
+	GetDeadLetterTTL() RetentionTTL
+	SetRetentionPolicy(policy RetentionPolicy)
+	GetEvictionStats() EvictionStats
+}
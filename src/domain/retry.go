@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryStrategy string
+
+const (
+	RetryStrategyFixed       RetryStrategy = "fixed"
+	RetryStrategyLinear      RetryStrategy = "linear"
+	RetryStrategyExponential RetryStrategy = "exponential"
+)
+
+const (
+	// MaxRetryDelayMs — предельная задержка между повторами (сутки),
+	// действует и когда MaxDelayMs не задан
+	MaxRetryDelayMs int64 = 24 * 60 * 60 * 1000
+
+	// maxRetryExponent ограничивает степень двойки экспоненциальной стратегии
+	maxRetryExponent = 32
+)
+
+// RetryPolicy описывает задержку перед повторным выполнением задачи
+// swagger:model RetryPolicy
+type RetryPolicy struct {
+	// Стратегия расчета задержки
+	// enum: fixed,linear,exponential
+	// example: "exponential"
+	Strategy RetryStrategy `json:"strategy"`
+
+	// Базовая задержка в миллисекундах
+	// example: 1000
+	DelayMs int64 `json:"delayMs"`
+
+	// Максимальная задержка в миллисекундах (0 — не более суток)
+	// example: 60000
+	MaxDelayMs int64 `json:"maxDelayMs,omitempty"`
+
+	// Доля случайного уменьшения задержки от 0 до 1
+	// example: 0.2
+	Jitter float64 `json:"jitter,omitempty"`
+}
+
+// Delay рассчитывает задержку перед попыткой attempt (нумерация с 1),
+// random — случайное число из [0, 1) для jitter
+func (p RetryPolicy) Delay(attempt int, random float64) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	base := float64(p.DelayMs)
+	var delay float64
+	switch p.Strategy {
+	case RetryStrategyLinear:
+		delay = base * float64(attempt)
+	case RetryStrategyExponential:
+		delay = base * math.Pow(2, float64(min(attempt-1, maxRetryExponent)))
+	default:
+		delay = base
+	}
+
+	limit := float64(MaxRetryDelayMs)
+	if p.MaxDelayMs > 0 && p.MaxDelayMs < MaxRetryDelayMs {
+		limit = float64(p.MaxDelayMs)
+	}
+	if delay > limit {
+		delay = limit
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * random
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// Fail фиксирует неудачную попытку выполнения задачи: при наличии попыток
+// задача переводится в retrying со сдвигом ScheduledAt по политике повторов,
+// иначе — в failed с переносом в DLQ очереди. Задача, отмена которой запрошена, не повторяется и переходит в cancelled
+func (t *Task) Fail(taskErr *TaskError, now time.Time) error {
+	return t.registerFailure(taskErr, now)
+}
+
+func (t *Task) registerFailure(taskErr *TaskError, now time.Time) error {
+	next := TaskStatusFailed
+	switch {
+	case t.CancelRequested:
+		next = TaskStatusCancelled
+	case t.RetryCount < t.MaxRetries:
+		next = TaskStatusRetrying
+	}
+	if err := t.transition(next, taskErr, now); err != nil {
+		return err
+	}
+	switch next {
+	case TaskStatusFailed:
+		t.deadLetter(DeadLetterReasonRetriesExhausted, now)
+	case TaskStatusRetrying:
+		t.RetryCount++
+		if t.RetryPolicy != nil {
+			retryAt := now.Add(t.RetryPolicy.Delay(t.RetryCount, rand.Float64()))
+			t.ScheduledAt = &retryAt
+		}
+	}
+	// Без описания ошибки сохраняется прежняя LastError
+	if taskErr != nil {
+		t.LastError = taskErr
+	}
+	t.releaseLease()
+	return nil
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailWithoutErrorKeepsLastError(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	previous := &TaskError{Message: "timeout", Code: "TIMEOUT"}
+
+	for _, maxRetries := range []int{0, 3} {
+		task := Task{Status: TaskStatusPending, MaxRetries: maxRetries, LastError: previous}
+		if err := task.Claim("w1", time.Minute, now); err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if err := task.Fail(nil, now); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+		if task.LastError != previous {
+			t.Fatalf("maxRetries %d: LastError after Fail(nil) = %v, want the previous error", maxRetries, task.LastError)
+		}
+
+		reported := &TaskError{Message: "boom"}
+		task = Task{Status: TaskStatusPending, MaxRetries: maxRetries, LastError: previous}
+		if err := task.Claim("w1", time.Minute, now); err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if err := task.Fail(reported, now); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+		if task.LastError != reported {
+			t.Fatalf("maxRetries %d: LastError = %v, want the reported error", maxRetries, task.LastError)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type MisfirePolicy string
+
+const (
+	// MisfirePolicyFireOnce однократно запускает пропущенное срабатывание
+	MisfirePolicyFireOnce MisfirePolicy = "fire_once"
+	// MisfirePolicySkip пропускает просроченное срабатывание
+	MisfirePolicySkip MisfirePolicy = "skip"
+)
+
+// TaskTemplate шаблон задачи, создаваемой расписанием
+// swagger:model TaskTemplate
+type TaskTemplate struct {
+	// Тип задачи
+	// example: "email_send"
+	Type string `json:"type"`
+
+	// Приоритет задачи
+	// enum: low,medium,high,critical
+	// example: "high"
+	Priority TaskPriority `json:"priority"`
+
+	// Полезная нагрузка задачи
+	// example: {"email": "user@example.com", "subject": "Test"}
+	Payload map[string]interface{} `json:"payload"`
+
+	// Метаданные задачи
+	// example: {"source": "api", "user_id": "123"}
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Максимальное количество попыток
+	// example: 3
+	MaxRetries int `json:"maxRetries"`
+
+	// Политика задержки между повторами
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// ID родительской задачи
+	// example: "task-123"
+	ParentTaskID string `json:"parentTaskId,omitempty"`
+
+	// Список зависимостей
+	// example: ["task-456", "task-789"]
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Очередь для выполнения
+	// example: "default"
+	Queue string `json:"queue"`
+}
+
+// Schedule периодическое создание задач по cron-выражению
+// swagger:model Schedule
+type Schedule struct {
+	// Уникальный идентификатор расписания
+	// example: "schedule-123"
+	ID string `json:"id"`
+
+	// Cron-выражение из пяти полей или дескриптор (@daily, @hourly, ...)
+	// example: "0 3 * * *"
+	Cron string `json:"cron"`
+
+	// Часовой пояс IANA, в котором вычисляется расписание
+	// example: "Europe/Moscow"
+	Timezone string `json:"timezone"`
+
+	// Поведение при пропущенном срабатывании
+	// enum: fire_once,skip
+	// example: "fire_once"
+	MisfirePolicy MisfirePolicy `json:"misfirePolicy"`
+
+	// Шаблон создаваемой задачи
+	Template TaskTemplate `json:"template"`
+
+	// Время создания расписания
+	// example: "2024-01-15T09:00:00Z"
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Время последнего срабатывания
+	// example: "2024-01-15T03:00:00Z"
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+
+	// ID задачи, созданной последним срабатыванием
+	// example: "task-123"
+	LastTaskID string `json:"lastTaskId,omitempty"`
+
+	// Количество пропущенных срабатываний
+	// example: 0
+	MissedRuns int `json:"missedRuns"`
+
+	// Ошибка последнего срабатывания, при котором не удалось создать задачу
+	// example: "dependency task-456 not found"
+	LastError string `json:"lastError,omitempty"`
+
+	// Время следующего срабатывания
+	// example: "2024-01-16T03:00:00Z"
+	NextRunAt time.Time `json:"nextRunAt"`
+}
+
+func (s Schedule) IsDue(now time.Time) bool {
+	return !s.NextRunAt.After(now)
+}
+
+// IsMisfired сообщает, что срабатывание опоздало больше чем на threshold
+func (s Schedule) IsMisfired(now time.Time, threshold time.Duration) bool {
+	return now.Sub(s.NextRunAt) > threshold
+}
+
+type IScheduleRepository interface {
+	Get(id string) (Schedule, bool)
+	Create(schedule Schedule) error
+	Update(id string, fn func(schedule *Schedule) error) (Schedule, error)
+	Delete(id string) error
+	GetAll(ctx context.Context) ([]Schedule, error)
+}
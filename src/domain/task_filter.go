@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskFilter условия выборки задач. Пустые поля не ограничивают выборку,
+// заданные условия объединяются по И
+type TaskFilter struct {
+	Status       TaskStatus
+	Queue        string
+	Type         string
+	Priority     TaskPriority
+	WorkerID     string
+	ParentTaskID string
+
+	// Границы времени создания и последнего изменения включительно; нулевое значение — без границы
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+
+	// Границы количества попыток включительно; nil — без границы
+	MinRetryCount *int
+	MaxRetryCount *int
+
+	// Metadata значения метаданных, которым задача должна быть равна по каждому ключу.
+	// Значения метаданных сравниваются в строковом представлении
+	Metadata map[string]string
+}
+
+func (f TaskFilter) Match(task *Task) bool {
+	switch {
+	case f.Status != "" && task.Status != f.Status,
+		f.Queue != "" && task.Queue != f.Queue,
+		f.Type != "" && task.Type != f.Type,
+		f.Priority != "" && task.Priority != f.Priority,
+		f.WorkerID != "" && task.WorkerID != f.WorkerID,
+		f.ParentTaskID != "" && task.ParentTaskID != f.ParentTaskID:
+		return false
+	}
+
+	if !inTimeRange(task.CreatedAt, f.CreatedFrom, f.CreatedTo) ||
+		!inTimeRange(task.UpdatedAt, f.UpdatedFrom, f.UpdatedTo) {
+		return false
+	}
+
+	if f.MinRetryCount != nil && task.RetryCount < *f.MinRetryCount {
+		return false
+	}
+	if f.MaxRetryCount != nil && task.RetryCount > *f.MaxRetryCount {
+		return false
+	}
+
+	for key, expected := range f.Metadata {
+		value, ok := task.Metadata[key]
+		if !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func inTimeRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && t.After(to) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"container/heap"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type TaskSortField string
+
+const (
+	TaskSortCreatedAt   TaskSortField = "createdAt"
+	TaskSortUpdatedAt   TaskSortField = "updatedAt"
+	TaskSortPriority    TaskSortField = "priority"
+	TaskSortScheduledAt TaskSortField = "scheduledAt"
+)
+
+// TaskSort порядок выдачи задач. При равенстве поля задачи упорядочиваются
+// по createdAt, затем по ID, поэтому порядок полный и не зависит от шардов
+type TaskSort struct {
+	Field TaskSortField
+	Desc  bool
+}
+
+// DefaultTaskSort совпадает с порядком выдачи задач воркерам
+var DefaultTaskSort = TaskSort{Field: TaskSortPriority, Desc: true}
+
+// ParseTaskSort разбирает порядок вида "field" или "field:asc|desc"
+func ParseTaskSort(value string) (TaskSort, error) {
+	if value == "" {
+		return DefaultTaskSort, nil
+	}
+	field, direction, _ := strings.Cut(value, ":")
+	sort := TaskSort{Field: TaskSortField(field)}
+	switch sort.Field {
+	case TaskSortCreatedAt, TaskSortUpdatedAt, TaskSortPriority, TaskSortScheduledAt:
+	default:
+		return TaskSort{}, fmt.Errorf("invalid sort field: %s, must be one of: createdAt, updatedAt, priority, scheduledAt", field)
+	}
+	switch direction {
+	case "", "asc":
+	case "desc":
+		sort.Desc = true
+	default:
+		return TaskSort{}, fmt.Errorf("invalid sort direction: %s, must be asc or desc", direction)
+	}
+	return sort, nil
+}
+
+func (s TaskSort) String() string {
+	if s.Desc {
+		return string(s.Field) + ":desc"
+	}
+	return string(s.Field) + ":asc"
+}
+
+// key значение поля сортировки; задачи без scheduledAt считаются запланированными позже всех
+func (s TaskSort) key(task *Task) int64 {
+	switch s.Field {
+	case TaskSortUpdatedAt:
+		return task.UpdatedAt.UnixNano()
+	case TaskSortPriority:
+		return int64(task.Priority.Weight())
+	case TaskSortScheduledAt:
+		if task.ScheduledAt == nil {
+			return math.MaxInt64
+		}
+		return task.ScheduledAt.UnixNano()
+	default:
+		return task.CreatedAt.UnixNano()
+	}
+}
+
+func (s TaskSort) compare(key int64, createdAt int64, id string, other TaskCursor) int {
+	if key != other.Key {
+		if (key < other.Key) != s.Desc {
+			return -1
+		}
+		return 1
+	}
+	if createdAt != other.CreatedAt {
+		if createdAt < other.CreatedAt {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(id, other.ID)
+}
+
+func (s TaskSort) cursor(task *Task) TaskCursor {
+	return TaskCursor{Sort: s.String(), Key: s.key(task), CreatedAt: task.CreatedAt.UnixNano(), ID: task.ID}
+}
+
+// Less сообщает, что задача a идет в выдаче раньше b
+func (s TaskSort) Less(a, b *Task) bool {
+	return s.compare(s.key(a), a.CreatedAt.UnixNano(), a.ID, s.cursor(b)) < 0
+}
+
+// TaskCursor позиция последней выданной задачи; передается клиенту в закодированном виде
+type TaskCursor struct {
+	Sort      string `json:"s"`
+	Key       int64  `json:"k"`
+	CreatedAt int64  `json:"c"`
+	ID        string `json:"i"`
+}
+
+func (c TaskCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTaskCursor разбирает курсор; курсор действителен только для того порядка, в котором он выдан
+func DecodeTaskCursor(value string, sort TaskSort) (*TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor TaskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort.String() {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %s", ErrInvalidCursor, cursor.Sort)
+	}
+	return &cursor, nil
+}
+
+// TaskQuery параметры выборки списка задач
+type TaskQuery struct {
+	Filter TaskFilter
+	// Predicate дополнительное условие, которое нельзя выразить фильтром; nil — без условия
+	Predicate func(task *Task) bool
+	Sort      TaskSort
+	// Limit максимальное количество задач на странице; 0 — без ограничения
+	Limit int
+	// After курсор последней задачи предыдущей страницы
+	After *TaskCursor
+}
+
+// Match сообщает, удовлетворяет ли задача фильтру и дополнительному условию запроса
+func (q TaskQuery) Match(task *Task) bool {
+	return q.Filter.Match(task) && (q.Predicate == nil || q.Predicate(task))
+}
+
+// TaskPage страница списка задач; NextCursor пуст, если страница последняя
+type TaskPage struct {
+	Tasks      []Task
+	NextCursor string
+}
+
+// TaskPager отбирает страницу задач из задач, переданных в произвольном порядке,
+// храня не больше Limit+1 задач
+type TaskPager struct {
+	query TaskQuery
+	items taskPageHeap
+}
+
+func NewTaskPager(query TaskQuery) *TaskPager {
+	return &TaskPager{query: query, items: taskPageHeap{sort: query.Sort}}
+}
+
+func (p *TaskPager) Add(task Task) {
+	sort := p.query.Sort
+	if p.query.After != nil && sort.compare(sort.key(&task), task.CreatedAt.UnixNano(), task.ID, *p.query.After) <= 0 {
+		return
+	}
+	heap.Push(&p.items, task)
+	if p.query.Limit > 0 && p.items.Len() > p.query.Limit+1 {
+		heap.Pop(&p.items)
+	}
+}
+
+func (p *TaskPager) Page() TaskPage {
+	tasks := make([]Task, p.items.Len())
+	for i := len(tasks) - 1; i >= 0; i-- {
+		tasks[i] = heap.Pop(&p.items).(Task)
+	}
+
+	page := TaskPage{Tasks: tasks}
+	if p.query.Limit > 0 && len(tasks) > p.query.Limit {
+		page.Tasks = tasks[:p.query.Limit]
+		page.NextCursor = p.query.Sort.cursor(&page.Tasks[p.query.Limit-1]).Encode()
+	}
+	return page
+}
+
+// taskPageHeap держит на вершине задачу, идущую в выдаче последней
+type taskPageHeap struct {
+	sort  TaskSort
+	tasks []Task
+}
+
+func (h taskPageHeap) Len() int           { return len(h.tasks) }
+func (h taskPageHeap) Less(i, j int) bool { return h.sort.Less(&h.tasks[j], &h.tasks[i]) }
+func (h taskPageHeap) Swap(i, j int)      { h.tasks[i], h.tasks[j] = h.tasks[j], h.tasks[i] }
+func (h *taskPageHeap) Push(x any)        { h.tasks = append(h.tasks, x.(Task)) }
+func (h *taskPageHeap) Pop() any {
+	old := h.tasks
+	task := old[len(old)-1]
+	h.tasks = old[:len(old)-1]
+	return task
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// taskTransitions таблица допустимых переходов между статусами задачи. Из failed
+// задачу возвращают только requeue из DLQ (в pending) и requeue ее зависимости (в blocked)
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusBlocked:    {TaskStatusScheduled, TaskStatusPending, TaskStatusFailed, TaskStatusCancelled},
+	TaskStatusScheduled:  {TaskStatusPending, TaskStatusCancelled},
+	TaskStatusPending:    {TaskStatusProcessing, TaskStatusCancelled},
+	TaskStatusProcessing: {TaskStatusCompleted, TaskStatusFailed, TaskStatusRetrying, TaskStatusCancelled},
+	TaskStatusRetrying:   {TaskStatusProcessing, TaskStatusCancelled},
+	TaskStatusCompleted:  {},
+	TaskStatusFailed:     {TaskStatusPending, TaskStatusBlocked},
+	TaskStatusCancelled:  {},
+}
+
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	for _, allowed := range taskTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo переводит задачу в новый статус и проставляет StartedAt/FinishedAt
+func (t *Task) TransitionTo(next TaskStatus, now time.Time) error {
+	return t.transition(next, nil, now)
+}
+
+// transition выполняет переход; при выходе из processing попытка попадает в историю
+// вместе с ошибкой taskErr, если она известна
+func (t *Task) transition(next TaskStatus, taskErr *TaskError, now time.Time) error {
+	if !t.Status.CanTransitionTo(next) {
+		return &TransitionError{From: t.Status, To: next}
+	}
+	if t.Status == TaskStatusProcessing {
+		t.finishAttempt(next, taskErr, now)
+	}
+
+	switch next {
+	case TaskStatusBlocked, TaskStatusPending:
+		t.FinishedAt = nil
+	case TaskStatusProcessing:
+		t.StartedAt = &now
+		t.FinishedAt = nil
+	case TaskStatusRetrying:
+		t.releaseLease()
+	case TaskStatusCompleted, TaskStatusFailed:
+		t.FinishedAt = &now
+		t.LeaseExpiresAt = nil
+	case TaskStatusCancelled:
+		t.FinishedAt = &now
+		t.CancelRequested = false
+		t.releaseLease()
+	}
+
+	t.Status = next
+	t.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var allTaskStatuses = []TaskStatus{
+	TaskStatusBlocked,
+	TaskStatusScheduled,
+	TaskStatusPending,
+	TaskStatusProcessing,
+	TaskStatusCompleted,
+	TaskStatusFailed,
+	TaskStatusRetrying,
+	TaskStatusCancelled,
+}
+
+func TestTaskTransitions(t *testing.T) {
+	// Допустимые переходы перечислены явно, а не взяты из taskTransitions,
+	// чтобы случайное изменение таблицы не прошло незамеченным
+	allowed := map[TaskStatus][]TaskStatus{
+		TaskStatusBlocked:    {TaskStatusScheduled, TaskStatusPending, TaskStatusFailed, TaskStatusCancelled},
+		TaskStatusScheduled:  {TaskStatusPending, TaskStatusCancelled},
+		TaskStatusPending:    {TaskStatusProcessing, TaskStatusCancelled},
+		TaskStatusProcessing: {TaskStatusCompleted, TaskStatusFailed, TaskStatusRetrying, TaskStatusCancelled},
+		TaskStatusRetrying:   {TaskStatusProcessing, TaskStatusCancelled},
+		TaskStatusFailed:     {TaskStatusPending, TaskStatusBlocked},
+	}
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(time.Minute)
+
+	for _, from := range allTaskStatuses {
+		for _, to := range allTaskStatuses {
+			want := false
+			for _, status := range allowed[from] {
+				if status == to {
+					want = true
+				}
+			}
+
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				if got := from.CanTransitionTo(to); got != want {
+					t.Fatalf("CanTransitionTo = %v, want %v", got, want)
+				}
+
+				task := Task{Status: from, UpdatedAt: created}
+				err := task.TransitionTo(to, now)
+				if !want {
+					var transitionErr *TransitionError
+					if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+						t.Fatalf("TransitionTo error = %v, want TransitionError %s -> %s", err, from, to)
+					}
+					if task.Status != from || !task.UpdatedAt.Equal(created) {
+						t.Fatalf("rejected transition changed the task: status %s, updatedAt %s", task.Status, task.UpdatedAt)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("TransitionTo: %v", err)
+				}
+				if task.Status != to || !task.UpdatedAt.Equal(now) {
+					t.Fatalf("task after transition: status %s, updatedAt %s", task.Status, task.UpdatedAt)
+				}
+			})
+		}
+	}
+}
+
+func TestTransitionTimestamps(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		from         TaskStatus
+		to           TaskStatus
+		wantStarted  bool
+		wantFinished bool
+	}{
+		{TaskStatusPending, TaskStatusProcessing, true, false},
+		{TaskStatusRetrying, TaskStatusProcessing, true, false},
+		{TaskStatusProcessing, TaskStatusCompleted, false, true},
+		{TaskStatusProcessing, TaskStatusFailed, false, true},
+		{TaskStatusProcessing, TaskStatusCancelled, false, true},
+		{TaskStatusBlocked, TaskStatusFailed, false, true},
+		{TaskStatusFailed, TaskStatusPending, false, false},
+		{TaskStatusFailed, TaskStatusBlocked, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			task := Task{Status: tt.from, FinishedAt: &earlier}
+			if err := task.TransitionTo(tt.to, now); err != nil {
+				t.Fatalf("TransitionTo: %v", err)
+			}
+			if started := task.StartedAt != nil && task.StartedAt.Equal(now); started != tt.wantStarted {
+				t.Fatalf("startedAt = %v, want set to now: %v", task.StartedAt, tt.wantStarted)
+			}
+			if tt.wantFinished {
+				if task.FinishedAt == nil || !task.FinishedAt.Equal(now) {
+					t.Fatalf("finishedAt = %v, want %s", task.FinishedAt, now)
+				}
+			} else if task.FinishedAt != nil {
+				t.Fatalf("finishedAt = %s, want nil", task.FinishedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+// TaskAggregate сводное состояние дочерних задач
+// swagger:model TaskAggregate
+type TaskAggregate struct {
+	// Сводный статус: failed, если хотя бы одна дочерняя задача failed;
+	// cancelled, если все cancelled; completed, если все завершены и хотя бы одна completed;
+	// processing, если выполнение началось; иначе pending
+	// example: "processing"
+	Status TaskStatus `json:"status"`
+
+	// Количество дочерних задач
+	// example: 3
+	Total int `json:"total"`
+
+	// Ожидают выполнения (blocked, scheduled, pending)
+	// example: 1
+	Pending int `json:"pending"`
+
+	// Выполняются (processing, retrying)
+	// example: 1
+	Running int `json:"running"`
+
+	// Выполнены успешно
+	// example: 1
+	Completed int `json:"completed"`
+
+	// Завершены с ошибкой
+	// example: 0
+	Failed int `json:"failed"`
+
+	// Отменены
+	// example: 0
+	Cancelled int `json:"cancelled"`
+}
+
+// TaskTree задача вместе со всеми потомками
+// swagger:model TaskTree
+type TaskTree struct {
+	Task     Task       `json:"task"`
+	Children []TaskTree `json:"children"`
+}
+
+func AggregateChildren(children []Task) TaskAggregate {
+	aggregate := TaskAggregate{Total: len(children)}
+	for _, child := range children {
+		switch child.Status {
+		case TaskStatusCompleted:
+			aggregate.Completed++
+		case TaskStatusFailed:
+			aggregate.Failed++
+		case TaskStatusCancelled:
+			aggregate.Cancelled++
+		case TaskStatusProcessing, TaskStatusRetrying:
+			aggregate.Running++
+		default:
+			aggregate.Pending++
+		}
+	}
+
+	switch {
+	case aggregate.Failed > 0:
+		aggregate.Status = TaskStatusFailed
+	case aggregate.Total > 0 && aggregate.Cancelled == aggregate.Total:
+		aggregate.Status = TaskStatusCancelled
+	case aggregate.Completed+aggregate.Cancelled == aggregate.Total:
+		aggregate.Status = TaskStatusCompleted
+	case aggregate.Running > 0 || aggregate.Completed > 0:
+		aggregate.Status = TaskStatusProcessing
+	default:
+		aggregate.Status = TaskStatusPending
+	}
+	return aggregate
+}
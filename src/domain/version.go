@@ -0,0 +1,19 @@
+package domain
+
+// AdvanceVersion назначает задаче версию, следующую за версией prev;
+// prev == nil для новой задачи. Вызывается хранилищем при каждой записи
+func (t *Task) AdvanceVersion(prev *Task) {
+	if prev == nil {
+		t.Version = 1
+		return
+	}
+	t.Version = prev.Version + 1
+}
+
+// CheckVersion проверяет, что задача не изменилась с версии expected
+func (t Task) CheckVersion(expected int64) error {
+	if t.Version != expected {
+		return &VersionConflictError{Expected: expected, Actual: t.Version}
+	}
+	return nil
+}
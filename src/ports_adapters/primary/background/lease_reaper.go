@@ -0,0 +1,46 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+	"svc-task_master/src/application"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+// LeaseReaper периодически возвращает в очередь задачи, воркеры которых перестали продлевать аренду
+type LeaseReaper struct {
+	app      *application.App
+	logger   domain.ILogger
+	interval time.Duration
+}
+
+func NewLeaseReaper(app *application.App, logger domain.ILogger, interval time.Duration) *LeaseReaper {
+	return &LeaseReaper{
+		app:      app,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+func (r *LeaseReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			released, err := r.app.Command.ReleaseExpiredLeases.Handle(ctx, dto.ReleaseExpiredLeasesRequest{Now: now})
+			if err != nil {
+				r.logger.Error("Failed to release expired leases", slog.String("error", err.Error()))
+				continue
+			}
+			if released > 0 {
+				r.logger.Info("Released expired leases", slog.Int("released_count", released))
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+	"svc-task_master/src/application"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+	"time"
+)
+
+// ScheduleRunner периодически создает задачи по наступившим расписаниям
+type ScheduleRunner struct {
+	app      *application.App
+	logger   domain.ILogger
+	interval time.Duration
+}
+
+func NewScheduleRunner(app *application.App, logger domain.ILogger, interval time.Duration) *ScheduleRunner {
+	return &ScheduleRunner{
+		app:      app,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+func (r *ScheduleRunner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			fired, err := r.app.Command.FireSchedules.Handle(ctx, dto.FireSchedulesRequest{Now: now})
+			if err != nil {
+				r.logger.Error("Failed to fire schedules", slog.String("error", err.Error()))
+				continue
+			}
+			if fired > 0 {
+				r.logger.Info("Fired schedules", slog.Int("fired_count", fired))
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	actorHeader     = "X-Actor"
+	requestIDHeader = "X-Request-ID"
+	// anonymousActor инициатор запросов без заголовка X-Actor
+	anonymousActor = "anonymous"
+)
+
+// WithAudit передает в контекст запроса инициатора изменений из заголовка X-Actor
+// и ID запроса для журнала аудита. Аутентификации в сервисе нет, поэтому инициатор
+// из заголовка записывается как заявленный клиентом. ID берется из X-Request-ID
+// или генерируется и возвращается клиенту в том же заголовке
+func WithAudit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		actor := r.Header.Get(actorHeader)
+		claimed := actor != ""
+		if !claimed {
+			actor = anonymousActor
+		}
+		next.ServeHTTP(w, r.WithContext(domain.WithAudit(r.Context(), actor, claimed, requestID)))
+	})
+}
@@ -0,0 +1,51 @@
+package http_server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// CancelTask отменяет задачу
+// @Summary Отмена задачи
+// @Description Невыполняющаяся задача сразу переходит в cancelled. У выполняющейся задачи выставляется
+// @Description cancelRequested: воркер узнает об отмене из ответа на heartbeat и переводит задачу в cancelled.
+// @Description С cascade=true так же отменяются дочерние и зависящие от нее задачи, рекурсивно
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Param cancel body dto.CancelTaskRequest false "Параметры отмены"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача отменена или отмена запрошена"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 409 {object} dto.Response "Задача уже завершена"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/cancel [post]
+func (s Server) CancelTask(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	var req dto.CancelTaskRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Id = id
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.CancelTask.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
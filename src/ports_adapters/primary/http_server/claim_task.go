@@ -0,0 +1,46 @@
+package http_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// ClaimTask выдает воркеру следующую задачу из очереди
+// @Summary Получение задачи из очереди
+// @Description Атомарно выбирает самую приоритетную ожидающую задачу очереди, переводит ее в processing и закрепляет за воркером
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param name path string true "Имя очереди"
+// @Param claim body dto.ClaimTaskRequest true "Данные воркера"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача выдана воркеру"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 404 {object} dto.Response "В очереди нет доступных задач"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /queue/{name}/claim [post]
+func (s Server) ClaimTask(w http.ResponseWriter, r *http.Request) {
+	queue := r.Context().Value("name").(string)
+	var req dto.ClaimTaskRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Queue = queue
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.ClaimTask.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
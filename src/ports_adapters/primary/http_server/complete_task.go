@@ -0,0 +1,47 @@
+package http_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// CompleteTask принимает отчет воркера о выполнении задачи
+// @Summary Отчет об успешном выполнении задачи
+// @Description Переводит задачу в completed и сохраняет output. Отчет принимается только от воркера, удерживающего аренду задачи
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Param report body dto.CompleteTaskRequest true "Отчет воркера"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача выполнена"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 409 {object} dto.Response "Задача не удерживается воркером"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/complete [post]
+func (s Server) CompleteTask(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	var req dto.CompleteTaskRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Id = id
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.CompleteTask.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,39 @@
+package http_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// CreateSchedule создает расписание периодических задач
+// @Summary Создание расписания
+// @Description Создает расписание, по которому задачи из шаблона создаются согласно cron-выражению
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param schedule body dto.CreateScheduleRequest true "Данные расписания"
+// @Success 200 {object} dto.Response{data=domain.Schedule} "Расписание создано"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /schedule [post]
+func (s Server) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.CreateSchedule.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
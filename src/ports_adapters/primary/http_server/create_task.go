@@ -8,13 +8,17 @@ import (
 
 // CreateTask создает новую задачу
 // @Summary Создание новой задачи
-// @Description Создает новую задачу в системе
+// @Description Создает новую задачу в системе и возвращает ее ID. Повторный запрос с тем же ключом
+// @Description идемпотентности (заголовок Idempotency-Key или поле dedupKey) в течение IDEMPOTENCY_TTL
+// @Description не создает новую задачу, а возвращает ID исходной с флагом replayed
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Ключ идемпотентности"
 // @Param task body dto.TaskRequest true "Данные для создания задачи"
-// @Success 200 {object} dto.Response{data=domain.Task} "Задача успешно создана"
-// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Success 200 {object} dto.Response{data=string} "Задача создана или возвращена ранее созданная"
+// @Header 200 {string} Idempotent-Replayed "true, если возвращена ранее созданная задача"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса или зависимости"
 // @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
 // @Router /task [post]
 func (s Server) CreateTask(w http.ResponseWriter, r *http.Request) {
@@ -24,6 +28,7 @@ func (s Server) CreateTask(w http.ResponseWriter, r *http.Request) {
 		response(w, nil, http.StatusBadRequest, err)
 		return
 	}
+	req.IdempotencyKey = r.Header.Get("Idempotency-Key")
 	err = req.Validate()
 	if err != nil {
 		response(w, nil, http.StatusBadRequest, err)
@@ -31,9 +36,9 @@ func (s Server) CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := s.app.Command.CreateTask.Handle(r.Context(), req)
 	if err != nil {
-		response(w, nil, http.StatusInternalServerError, err)
+		response(w, nil, errorStatus(err), err)
 		return
 	}
-	response(w, res, http.StatusOK, nil)
+	responseCreated(w, res)
 
 }
@@ -0,0 +1,37 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// DeleteSchedule удаляет расписание
+// @Summary Удаление расписания
+// @Description Удаляет расписание; уже созданные им задачи не затрагиваются
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "ID расписания"
+// @Success 200 {object} dto.Response "Расписание удалено"
+// @Failure 400 {object} dto.Response "Некорректный ID расписания"
+// @Failure 404 {object} dto.Response "Расписание не найдено"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /schedule/{id} [delete]
+func (s Server) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	req := dto.DeleteScheduleRequest{
+		ID: id,
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.DeleteSchedule.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
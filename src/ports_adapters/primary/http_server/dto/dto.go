@@ -3,6 +3,9 @@ package dto
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"svc-task_master/src/common/cron"
 	"svc-task_master/src/domain"
 	"time"
 )
@@ -44,6 +47,9 @@ type TaskRequest struct {
 	// example: 3
 	MaxRetries int `json:"maxRetries"`
 
+	// Политика задержки между повторами
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
 	// ID родительской задачи
 	// example: "task-123"
 	ParentTaskID string `json:"parentTaskId,omitempty"`
@@ -56,6 +62,24 @@ type TaskRequest struct {
 	// required: true
 	// example: "default"
 	Queue string `json:"queue"`
+
+	// Ключ идемпотентности: повторное создание с тем же ключом в течение IDEMPOTENCY_TTL
+	// возвращает ID уже созданной задачи. Альтернатива заголовку Idempotency-Key
+	// example: "order-42-email"
+	DedupKey string `json:"dedupKey,omitempty"`
+
+	// Значение заголовка Idempotency-Key
+	IdempotencyKey string `json:"-"`
+}
+
+const maxIdempotencyKeyLength = 255
+
+// Key ключ идемпотентности запроса: заголовок Idempotency-Key или поле dedupKey
+func (t *TaskRequest) Key() string {
+	if t.IdempotencyKey != "" {
+		return t.IdempotencyKey
+	}
+	return t.DedupKey
 }
 
 func (t *TaskRequest) Validate() error {
@@ -88,6 +112,12 @@ func (t *TaskRequest) Validate() error {
 		return errors.New("retry count cannot exceed max retries")
 	}
 
+	if t.RetryPolicy != nil {
+		if err := t.RetryPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if t.ScheduledAt != nil && t.ScheduledAt.Before(time.Now()) {
 		return errors.New("scheduled time must be in the future")
 	}
@@ -100,6 +130,66 @@ func (t *TaskRequest) Validate() error {
 		return errors.New("queue is required")
 	}
 
+	if t.IdempotencyKey != "" && t.DedupKey != "" && t.IdempotencyKey != t.DedupKey {
+		return errors.New("Idempotency-Key header and dedupKey must match when both are set")
+	}
+	if len(t.Key()) > maxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key is too long, must be at most %d characters", maxIdempotencyKeyLength)
+	}
+
+	return nil
+}
+
+// RetryPolicy политика задержки между повторами задачи
+// swagger:model RetryPolicy
+type RetryPolicy struct {
+	// Стратегия расчета задержки
+	// required: true
+	// enum: fixed,linear,exponential
+	// example: "exponential"
+	Strategy string `json:"strategy"`
+
+	// Базовая задержка в миллисекундах
+	// minimum: 0
+	// maximum: 86400000
+	// example: 1000
+	DelayMs int64 `json:"delayMs"`
+
+	// Максимальная задержка в миллисекундах (0 — не более суток)
+	// minimum: 0
+	// maximum: 86400000
+	// example: 60000
+	MaxDelayMs int64 `json:"maxDelayMs,omitempty"`
+
+	// Доля случайного уменьшения задержки от 0 до 1
+	// example: 0.2
+	Jitter float64 `json:"jitter,omitempty"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	validStrategies := map[string]bool{
+		string(domain.RetryStrategyFixed):       true,
+		string(domain.RetryStrategyLinear):      true,
+		string(domain.RetryStrategyExponential): true,
+	}
+	if !validStrategies[p.Strategy] {
+		return fmt.Errorf("invalid retry strategy: %s, must be one of: fixed, linear, exponential", p.Strategy)
+	}
+	if p.DelayMs < 0 {
+		return errors.New("retry delay cannot be negative")
+	}
+	if p.MaxDelayMs < 0 {
+		return errors.New("retry max delay cannot be negative")
+	}
+	if p.MaxDelayMs > 0 && p.MaxDelayMs < p.DelayMs {
+		return errors.New("retry max delay cannot be less than delay")
+	}
+	if p.DelayMs > domain.MaxRetryDelayMs || p.MaxDelayMs > domain.MaxRetryDelayMs {
+		return fmt.Errorf("retry delay cannot exceed %d ms", domain.MaxRetryDelayMs)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New("retry jitter must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -117,11 +207,17 @@ type GetTaskRequest struct {
 type UpdateTaskStatusRequest struct {
 	// Новый статус задачи
 	// required: true
-	// enum: pending,processing,completed,failed,retrying
+	// enum: pending,completed,failed,cancelled
 	// example: "completed"
 	Status string `json:"status"`
 
 	Id string `json:"id"`
+
+	// Значение заголовка If-Match: ETag задачи, на который рассчитано обновление
+	IfMatch string `json:"-"`
+
+	// Ожидаемая версия задачи из If-Match; nil — обновление без проверки версии
+	ExpectedVersion *int64 `json:"-"`
 }
 
 func (t *UpdateTaskStatusRequest) Validate() error {
@@ -131,23 +227,56 @@ func (t *UpdateTaskStatusRequest) Validate() error {
 	if t.Id == "" {
 		return errors.New("task id is required")
 	}
+	// processing проставляет воркера и аренду, retrying расходует попытку,
+	// поэтому эти переходы доступны только через claim и fail
+	switch domain.TaskStatus(t.Status) {
+	case domain.TaskStatusProcessing:
+		return errors.New("status processing is set only by POST /queue/{name}/claim")
+	case domain.TaskStatusRetrying:
+		return errors.New("status retrying is set only by POST /task/{id}/fail or failed status")
+	}
 	validStatuses := map[string]bool{
-		string(domain.TaskStatusPending):    true,
-		string(domain.TaskStatusProcessing): true,
-		string(domain.TaskStatusCompleted):  true,
-		string(domain.TaskStatusFailed):     true,
-		string(domain.TaskStatusRetrying):   true,
+		string(domain.TaskStatusPending):   true,
+		string(domain.TaskStatusCompleted): true,
+		string(domain.TaskStatusFailed):    true,
+		string(domain.TaskStatusCancelled): true,
 	}
 
 	if !validStatuses[t.Status] {
 		return fmt.Errorf(
-			"invalid status: %s, must be one of: pending, processing, completed, failed, retrying",
+			"invalid status: %s, must be one of: pending, completed, failed, cancelled",
 			t.Status,
 		)
 	}
+
+	version, err := parseIfMatch(t.IfMatch)
+	if err != nil {
+		return err
+	}
+	t.ExpectedVersion = version
 	return nil
 }
 
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (t UpdateTaskStatusRequest) TaskID() string {
+	return t.Id
+}
+
+// parseIfMatch разбирает заголовок If-Match с ETag задачи вида "3" (допускается и W/"3").
+// Пустой заголовок и * не ограничивают версию
+func parseIfMatch(value string) (*int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("invalid If-Match header: %s, must be a task ETag", value)
+	}
+	return &version, nil
+}
+
 func (t *GetTaskRequest) Validate() error {
 	if t.ID == "" {
 		return errors.New("task id is required")
@@ -155,30 +284,237 @@ func (t *GetTaskRequest) Validate() error {
 	return nil
 }
 
+// GetTaskChildrenRequest структура запроса для получения дочерних задач
+type GetTaskChildrenRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetTaskChildrenRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+// GetTaskAttemptsRequest структура запроса для получения истории попыток задачи
+type GetTaskAttemptsRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetTaskAttemptsRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+// GetTaskTreeRequest структура запроса для получения дерева задач
+type GetTaskTreeRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetTaskTreeRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+const (
+	defaultTaskLimit = 100
+	maxTaskLimit     = 1000
+)
+
 // GetTaskWhithFiltersRequest структура запроса для получения задач с фильтрами
 // swagger:model GetTaskWhithFiltersRequest
 type GetTaskWhithFiltersRequest struct {
 	// Статус для фильтрации задач
-	// enum: pending,processing,completed,failed,retrying
+	// enum: blocked,scheduled,pending,processing,completed,failed,retrying,cancelled
 	// example: "pending"
 	Status string `json:"status"`
+
+	// Очередь задачи
+	// example: "default"
+	Queue string `json:"queue"`
+
+	// Тип задачи
+	// example: "email_send"
+	Type string `json:"type"`
+
+	// Приоритет задачи
+	// enum: low,medium,high,critical
+	// example: "high"
+	Priority string `json:"priority"`
+
+	// ID воркера, выполняющего задачу
+	// example: "worker-1"
+	WorkerID string `json:"workerId"`
+
+	// ID родительской задачи
+	// example: "task-123"
+	ParentID string `json:"parentId"`
+
+	// Границы времени создания в формате RFC3339, включительно
+	// example: "2024-01-15T09:00:00Z"
+	CreatedFrom string `json:"createdFrom"`
+	CreatedTo   string `json:"createdTo"`
+
+	// Границы времени последнего изменения в формате RFC3339, включительно
+	// example: "2024-01-15T09:00:00Z"
+	UpdatedFrom string `json:"updatedFrom"`
+	UpdatedTo   string `json:"updatedTo"`
+
+	// Границы количества попыток выполнения, включительно
+	// example: "1"
+	MinRetries string `json:"minRetries"`
+	MaxRetries string `json:"maxRetries"`
+
+	// Условия на метаданные в виде ключ:значение; все условия должны выполняться
+	// example: ["source:api"]
+	Metadata []string `json:"metadata"`
+
+	// Максимальное количество задач на странице (1-1000, по умолчанию 100)
+	// example: "50"
+	Limit string `json:"limit"`
+
+	// Курсор следующей страницы из поля nextCursor предыдущего ответа
+	Cursor string `json:"cursor"`
+
+	// Порядок выдачи: поле[:asc|desc], поле — createdAt, updatedAt, priority, scheduledAt
+	// example: "createdAt:desc"
+	Sort string `json:"sort"`
+
+	Query domain.TaskQuery `json:"-"`
 }
 
 func (r *GetTaskWhithFiltersRequest) Validate() error {
+	if err := r.validateStatus(); err != nil {
+		return err
+	}
+	filter, err := r.filter()
+	if err != nil {
+		return err
+	}
+
+	r.Query, err = parseTaskPage(r.Limit, r.Cursor, r.Sort)
+	r.Query.Filter = filter
+	return err
+}
+
+// parseTaskPage разбирает общие для списков задач параметры страницы
+func parseTaskPage(limitValue, cursor, sortValue string) (domain.TaskQuery, error) {
+	sort, err := domain.ParseTaskSort(sortValue)
+	if err != nil {
+		return domain.TaskQuery{}, err
+	}
+	query := domain.TaskQuery{
+		Sort:  sort,
+		Limit: defaultTaskLimit,
+	}
+	if limitValue != "" {
+		limit, err := strconv.Atoi(limitValue)
+		if err != nil || limit < 1 || limit > maxTaskLimit {
+			return domain.TaskQuery{}, fmt.Errorf("invalid limit: %s, must be between 1 and %d", limitValue, maxTaskLimit)
+		}
+		query.Limit = limit
+	}
+	if cursor != "" {
+		if query.After, err = domain.DecodeTaskCursor(cursor, sort); err != nil {
+			return domain.TaskQuery{}, err
+		}
+	}
+	return query, nil
+}
+
+func (r *GetTaskWhithFiltersRequest) filter() (domain.TaskFilter, error) {
+	filter := domain.TaskFilter{
+		Status:       domain.TaskStatus(r.Status),
+		Queue:        r.Queue,
+		Type:         r.Type,
+		Priority:     domain.TaskPriority(r.Priority),
+		WorkerID:     r.WorkerID,
+		ParentTaskID: r.ParentID,
+	}
+
+	switch filter.Priority {
+	case "", domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh, domain.TaskPriorityCritical:
+	default:
+		return filter, fmt.Errorf("invalid priority: %s, must be one of: low, medium, high, critical", r.Priority)
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseFilterTime("createdFrom", r.CreatedFrom); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseFilterTime("createdTo", r.CreatedTo); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedFrom, err = parseFilterTime("updatedFrom", r.UpdatedFrom); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedTo, err = parseFilterTime("updatedTo", r.UpdatedTo); err != nil {
+		return filter, err
+	}
+	if filter.MinRetryCount, err = parseFilterCount("minRetries", r.MinRetries); err != nil {
+		return filter, err
+	}
+	if filter.MaxRetryCount, err = parseFilterCount("maxRetries", r.MaxRetries); err != nil {
+		return filter, err
+	}
+
+	if len(r.Metadata) > 0 {
+		filter.Metadata = make(map[string]string, len(r.Metadata))
+		for _, condition := range r.Metadata {
+			key, value, ok := strings.Cut(condition, ":")
+			if !ok || key == "" {
+				return filter, fmt.Errorf("invalid metadata: %s, must be key:value", condition)
+			}
+			filter.Metadata[key] = value
+		}
+	}
+	return filter, nil
+}
+
+func parseFilterTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %s, must be RFC3339 time", name, value)
+	}
+	return t, nil
+}
+
+func parseFilterCount(name, value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid %s: %s, must be a non-negative integer", name, value)
+	}
+	return &count, nil
+}
+
+func (r *GetTaskWhithFiltersRequest) validateStatus() error {
 	if r.Status == "" {
 		return nil
 	}
 	validStatuses := map[string]bool{
+		string(domain.TaskStatusBlocked):    true,
+		string(domain.TaskStatusScheduled):  true,
 		string(domain.TaskStatusPending):    true,
 		string(domain.TaskStatusProcessing): true,
 		string(domain.TaskStatusCompleted):  true,
 		string(domain.TaskStatusFailed):     true,
 		string(domain.TaskStatusRetrying):   true,
+		string(domain.TaskStatusCancelled):  true,
 	}
 
 	if !validStatuses[r.Status] {
 		return fmt.Errorf(
-			"invalid status: %s, must be one of: pending, processing, completed, failed, retrying",
+			"invalid status: %s, must be one of: blocked, scheduled, pending, processing, completed, failed, retrying, cancelled",
 			r.Status,
 		)
 	}
@@ -186,6 +522,478 @@ func (r *GetTaskWhithFiltersRequest) Validate() error {
 	return nil
 }
 
+// SearchTasksRequest структура запроса для поиска задач по выражению на языке запросов
+// swagger:model SearchTasksRequest
+type SearchTasksRequest struct {
+	// Выражение поиска
+	// example: "queue = \"billing\" AND priority IN (\"high\", \"critical\") AND retryCount > 2"
+	Q string `json:"q"`
+
+	// Максимальное количество задач на странице (1-1000, по умолчанию 100)
+	// example: "50"
+	Limit string `json:"limit"`
+
+	// Курсор следующей страницы из поля nextCursor предыдущего ответа
+	Cursor string `json:"cursor"`
+
+	// Порядок выдачи: поле[:asc|desc], поле — createdAt, updatedAt, priority, scheduledAt
+	// example: "createdAt:desc"
+	Sort string `json:"sort"`
+
+	Query domain.TaskQuery `json:"-"`
+}
+
+func (r *SearchTasksRequest) Validate() error {
+	if strings.TrimSpace(r.Q) == "" {
+		return errors.New("query q is required")
+	}
+	var err error
+	r.Query, err = parseTaskPage(r.Limit, r.Cursor, r.Sort)
+	return err
+}
+
+// ClaimTaskRequest структура запроса на получение задачи воркером из очереди
+// swagger:model ClaimTaskRequest
+type ClaimTaskRequest struct {
+	// ID воркера, забирающего задачу
+	// required: true
+	// example: "worker-1"
+	WorkerID string `json:"workerId"`
+
+	// Длительность аренды в секундах (по умолчанию LEASE_TTL)
+	// minimum: 0
+	// example: 30
+	LeaseSeconds int `json:"leaseSeconds,omitempty"`
+
+	Queue string `json:"-"`
+}
+
+func (r *ClaimTaskRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("queue is required")
+	}
+	if r.WorkerID == "" {
+		return errors.New("worker id is required")
+	}
+	if r.LeaseSeconds < 0 {
+		return errors.New("lease seconds cannot be negative")
+	}
+	return nil
+}
+
+// CancelTaskRequest структура запроса на отмену задачи
+// swagger:model CancelTaskRequest
+type CancelTaskRequest struct {
+	// Отменить также дочерние задачи и задачи, зависящие от отменяемой, рекурсивно
+	// example: true
+	Cascade bool `json:"cascade"`
+
+	Id string `json:"-"`
+}
+
+func (r *CancelTaskRequest) Validate() error {
+	if r.Id == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (r CancelTaskRequest) TaskID() string {
+	return r.Id
+}
+
+// HeartbeatRequest структура запроса на продление аренды задачи
+// swagger:model HeartbeatRequest
+type HeartbeatRequest struct {
+	// ID воркера, удерживающего задачу
+	// required: true
+	// example: "worker-1"
+	WorkerID string `json:"workerId"`
+
+	// Новая длительность аренды в секундах от текущего момента (по умолчанию LEASE_TTL)
+	// minimum: 0
+	// example: 30
+	LeaseSeconds int `json:"leaseSeconds,omitempty"`
+
+	Id string `json:"-"`
+}
+
+func (r *HeartbeatRequest) Validate() error {
+	if r.Id == "" {
+		return errors.New("task id is required")
+	}
+	if r.WorkerID == "" {
+		return errors.New("worker id is required")
+	}
+	if r.LeaseSeconds < 0 {
+		return errors.New("lease seconds cannot be negative")
+	}
+	return nil
+}
+
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (r HeartbeatRequest) TaskID() string {
+	return r.Id
+}
+
+// CompleteTaskRequest структура отчета воркера об успешном выполнении задачи
+// swagger:model CompleteTaskRequest
+type CompleteTaskRequest struct {
+	// ID воркера, удерживающего задачу
+	// required: true
+	// example: "worker-1"
+	WorkerID string `json:"workerId"`
+
+	// Результат выполнения задачи
+	// example: {"messageId": "abc-123"}
+	Output interface{} `json:"output,omitempty"`
+
+	Id string `json:"-"`
+}
+
+func (r *CompleteTaskRequest) Validate() error {
+	if r.Id == "" {
+		return errors.New("task id is required")
+	}
+	if r.WorkerID == "" {
+		return errors.New("worker id is required")
+	}
+	return nil
+}
+
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (r CompleteTaskRequest) TaskID() string {
+	return r.Id
+}
+
+// TaskErrorRequest описание ошибки выполнения задачи
+// swagger:model TaskErrorRequest
+type TaskErrorRequest struct {
+	// Сообщение об ошибке
+	// required: true
+	// example: "Failed to send email"
+	Message string `json:"message"`
+
+	// Стек вызовов
+	// example: "main.main()\n\tmain.go:25 +0x123"
+	Stack string `json:"stack,omitempty"`
+
+	// Код ошибки
+	// example: "EMAIL_SEND_FAILED"
+	Code string `json:"code,omitempty"`
+}
+
+// FailTaskRequest структура отчета воркера об ошибке выполнения задачи
+// swagger:model FailTaskRequest
+type FailTaskRequest struct {
+	// ID воркера, удерживающего задачу
+	// required: true
+	// example: "worker-1"
+	WorkerID string `json:"workerId"`
+
+	// Ошибка выполнения
+	// required: true
+	Error *TaskErrorRequest `json:"error"`
+
+	// Ошибка окончательная: повтор не поможет, задача сразу переходит в failed
+	// example: false
+	Final bool `json:"final"`
+
+	Id string `json:"-"`
+}
+
+func (r *FailTaskRequest) Validate() error {
+	if r.Id == "" {
+		return errors.New("task id is required")
+	}
+	if r.WorkerID == "" {
+		return errors.New("worker id is required")
+	}
+	if r.Error == nil || r.Error.Message == "" {
+		return errors.New("error message is required")
+	}
+	return nil
+}
+
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (r FailTaskRequest) TaskID() string {
+	return r.Id
+}
+
+// ReleaseExpiredLeasesRequest запрос фонового освобождения задач с истекшей арендой
+type ReleaseExpiredLeasesRequest struct {
+	Now time.Time
+}
+
+// CreateScheduleRequest структура запроса для создания расписания
+// swagger:model CreateScheduleRequest
+type CreateScheduleRequest struct {
+	// Cron-выражение из пяти полей или дескриптор (@daily, @hourly, ...)
+	// required: true
+	// example: "0 3 * * *"
+	Cron string `json:"cron"`
+
+	// Часовой пояс IANA (по умолчанию UTC)
+	// example: "Europe/Moscow"
+	Timezone string `json:"timezone,omitempty"`
+
+	// Поведение при пропущенном срабатывании (по умолчанию fire_once)
+	// enum: fire_once,skip
+	// example: "fire_once"
+	MisfirePolicy string `json:"misfirePolicy,omitempty"`
+
+	// Шаблон создаваемой задачи
+	// required: true
+	Task TaskRequest `json:"task"`
+}
+
+func (r *CreateScheduleRequest) Validate() error {
+	if r.Cron == "" {
+		return errors.New("cron expression is required")
+	}
+	expr, err := cron.Parse(r.Cron)
+	if err != nil {
+		return err
+	}
+
+	if r.Timezone == "" {
+		r.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %s", r.Timezone)
+	}
+	if expr.Next(time.Now().In(loc)).IsZero() {
+		return errors.New("cron expression never fires")
+	}
+
+	if r.MisfirePolicy == "" {
+		r.MisfirePolicy = string(domain.MisfirePolicyFireOnce)
+	}
+	if r.MisfirePolicy != string(domain.MisfirePolicyFireOnce) && r.MisfirePolicy != string(domain.MisfirePolicySkip) {
+		return fmt.Errorf("invalid misfire policy: %s, must be one of: fire_once, skip", r.MisfirePolicy)
+	}
+
+	if r.Task.ScheduledAt != nil {
+		return errors.New("scheduled time is not allowed in schedule task template")
+	}
+	return r.Task.Validate()
+}
+
+// GetSchedulesRequest структура запроса для получения списка расписаний
+type GetSchedulesRequest struct{}
+
+// DeleteScheduleRequest структура запроса для удаления расписания
+type DeleteScheduleRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *DeleteScheduleRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("schedule id is required")
+	}
+	return nil
+}
+
+// FireSchedulesRequest запрос фонового запуска наступивших расписаний
+type FireSchedulesRequest struct {
+	Now time.Time
+}
+
+// GetRetentionRequest структура запроса для получения правил хранения задач
+type GetRetentionRequest struct{}
+
+// UpdateRetentionRequest структура запроса на замену правил хранения задач
+// swagger:model UpdateRetentionRequest
+type UpdateRetentionRequest struct {
+	// Правила хранения; к задаче применяется самое точное подходящее правило
+	// (очередь и статус, затем только очередь, затем только статус),
+	// задачи без подходящего правила не удаляются
+	// required: true
+	Rules []domain.RetentionRule `json:"rules"`
+}
+
+func (r *UpdateRetentionRequest) Validate() error {
+	if r.Rules == nil {
+		return errors.New("rules are required")
+	}
+	return domain.RetentionPolicy{Rules: r.Rules}.Validate()
+}
+
+// GetArchivedTaskRequest структура запроса для получения архивной задачи по ID
+type GetArchivedTaskRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetArchivedTaskRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+const (
+	defaultArchiveLimit = 100
+	maxArchiveLimit     = 1000
+)
+
+// GetArchivedTasksRequest структура запроса для поиска архивных задач по времени последнего изменения
+type GetArchivedTasksRequest struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Limit string `json:"limit"`
+
+	FromTime time.Time `json:"-"`
+	ToTime   time.Time `json:"-"`
+	MaxItems int       `json:"-"`
+}
+
+func (r *GetArchivedTasksRequest) Validate() error {
+	var err error
+	if r.From != "" {
+		if r.FromTime, err = time.Parse(time.RFC3339, r.From); err != nil {
+			return fmt.Errorf("invalid from: %s, must be RFC3339 time", r.From)
+		}
+	}
+	if r.To != "" {
+		if r.ToTime, err = time.Parse(time.RFC3339, r.To); err != nil {
+			return fmt.Errorf("invalid to: %s, must be RFC3339 time", r.To)
+		}
+	}
+	if !r.FromTime.IsZero() && !r.ToTime.IsZero() && r.ToTime.Before(r.FromTime) {
+		return errors.New("to must not be before from")
+	}
+
+	r.MaxItems = defaultArchiveLimit
+	if r.Limit != "" {
+		limit, err := strconv.Atoi(r.Limit)
+		if err != nil || limit < 1 || limit > maxArchiveLimit {
+			return fmt.Errorf("invalid limit: %s, must be between 1 and %d", r.Limit, maxArchiveLimit)
+		}
+		r.MaxItems = limit
+	}
+	return nil
+}
+
+// GetTaskAuditRequest структура запроса для получения журнала аудита задачи
+type GetTaskAuditRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetTaskAuditRequest) Validate() error {
+	if r.ID == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// GetAuditRequest структура запроса для поиска записей аудита по инициатору и времени
+type GetAuditRequest struct {
+	Actor string `json:"actor"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Limit string `json:"limit"`
+
+	FromTime time.Time `json:"-"`
+	ToTime   time.Time `json:"-"`
+	MaxItems int       `json:"-"`
+}
+
+func (r *GetAuditRequest) Validate() error {
+	var err error
+	if r.From != "" {
+		if r.FromTime, err = time.Parse(time.RFC3339, r.From); err != nil {
+			return fmt.Errorf("invalid from: %s, must be RFC3339 time", r.From)
+		}
+	}
+	if r.To != "" {
+		if r.ToTime, err = time.Parse(time.RFC3339, r.To); err != nil {
+			return fmt.Errorf("invalid to: %s, must be RFC3339 time", r.To)
+		}
+	}
+	if !r.FromTime.IsZero() && !r.ToTime.IsZero() && r.ToTime.Before(r.FromTime) {
+		return errors.New("to must not be before from")
+	}
+
+	r.MaxItems = defaultAuditLimit
+	if r.Limit != "" {
+		limit, err := strconv.Atoi(r.Limit)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			return fmt.Errorf("invalid limit: %s, must be between 1 and %d", r.Limit, maxAuditLimit)
+		}
+		r.MaxItems = limit
+	}
+	return nil
+}
+
+// GetDeadLettersRequest структура запроса для получения страницы DLQ очереди
+type GetDeadLettersRequest struct {
+	Queue  string `json:"-"`
+	Limit  string `json:"limit"`
+	Cursor string `json:"cursor"`
+	Sort   string `json:"sort"`
+
+	Query domain.TaskQuery `json:"-"`
+}
+
+func (r *GetDeadLettersRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("queue name is required")
+	}
+	var err error
+	r.Query, err = parseTaskPage(r.Limit, r.Cursor, r.Sort)
+	return err
+}
+
+// DeadLetterRequest структура запроса к задаче в DLQ очереди
+type DeadLetterRequest struct {
+	Queue string `json:"-"`
+	Id    string `json:"-"`
+}
+
+func (r *DeadLetterRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("queue name is required")
+	}
+	if r.Id == "" {
+		return errors.New("task id is required")
+	}
+	return nil
+}
+
+// TaskID ID задачи, над которой выполняется команда; используется журналом аудита
+func (r DeadLetterRequest) TaskID() string {
+	return r.Id
+}
+
+// DeadLettersRequest структура запроса на повтор или удаление задач из DLQ очереди
+// swagger:model DeadLettersRequest
+type DeadLettersRequest struct {
+	// ID задач; если не заданы, обрабатываются все задачи DLQ очереди
+	// example: ["550e8400-e29b-41d4-a716-446655440000"]
+	IDs []string `json:"ids,omitempty"`
+
+	Queue string `json:"-"`
+}
+
+func (r *DeadLettersRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("queue name is required")
+	}
+	for _, id := range r.IDs {
+		if id == "" {
+			return errors.New("task id cannot be empty")
+		}
+	}
+	return nil
+}
+
 // Response универсальная структура ответа API
 // swagger:model Response
 type Response struct {
@@ -199,4 +1007,10 @@ type Response struct {
 	// Сообщение об ошибке (если есть)
 	// example: "invalid request"
 	Error *string `json:"error,omitempty"`
+
+	// Курсор следующей страницы; отсутствует, если страница последняя
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// Запрос повторен с уже использованным ключом идемпотентности, и возвращен результат исходного запроса
+	Replayed bool `json:"replayed,omitempty"`
 }
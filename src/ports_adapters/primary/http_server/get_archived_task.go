@@ -0,0 +1,37 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetArchivedTask получает архивную задачу по ID
+// @Summary Получение архивной задачи по ID
+// @Description Возвращает последнюю архивную копию задачи, удаленной из хранилища по правилам хранения
+// @Tags archive
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=domain.ArchivedTask} "Архивная задача найдена"
+// @Failure 400 {object} dto.Response "Некорректный ID задачи"
+// @Failure 404 {object} dto.Response "Задача не найдена в архиве или архив отключен"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /archive/task/{id} [get]
+func (s Server) GetArchivedTask(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	req := dto.GetArchivedTaskRequest{
+		ID: id,
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetArchivedTask.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,41 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetArchivedTasks ищет архивные задачи по времени последнего изменения
+// @Summary Поиск архивных задач
+// @Description Возвращает архивные задачи, последнее изменение которых попадает в интервал, в порядке изменения
+// @Tags archive
+// @Accept json
+// @Produce json
+// @Param from query string false "Начало интервала (RFC3339)"
+// @Param to query string false "Конец интервала (RFC3339)"
+// @Param limit query int false "Максимальное количество задач (1-1000, по умолчанию 100)"
+// @Success 200 {object} dto.Response{data=[]domain.ArchivedTask} "Архивные задачи получены"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 404 {object} dto.Response "Архив отключен"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /archive [get]
+func (s Server) GetArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.GetArchivedTasksRequest{
+		From:  query.Get("from"),
+		To:    query.Get("to"),
+		Limit: query.Get("limit"),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetArchivedTasks.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,42 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetAudit ищет записи журнала аудита
+// @Summary Поиск в журнале аудита
+// @Description Возвращает записи аудита по инициатору и интервалу времени в порядке выполнения команд
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param actor query string false "Инициатор изменения (заголовок X-Actor; system — изменения самого сервиса)"
+// @Param from query string false "Начало интервала (RFC3339)"
+// @Param to query string false "Конец интервала (RFC3339)"
+// @Param limit query int false "Максимальное количество записей (1-1000, по умолчанию 100)"
+// @Success 200 {object} dto.Response{data=[]domain.AuditEntry} "Записи аудита получены"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /audit [get]
+func (s Server) GetAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.GetAuditRequest{
+		Actor: query.Get("actor"),
+		From:  query.Get("from"),
+		To:    query.Get("to"),
+		Limit: query.Get("limit"),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetAudit.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,39 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetDeadLetter получает задачу из DLQ очереди
+// @Summary Получение задачи из DLQ
+// @Description Возвращает задачу из DLQ очереди с контекстом ошибки: deadLetter, lastError, retryCount и история попыток
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача получена"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 404 {object} dto.Response "Задачи нет в DLQ очереди"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue}/task/{id} [get]
+func (s Server) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	req := dto.DeadLetterRequest{
+		Queue: r.Context().Value("queue").(string),
+		Id:    r.Context().Value("id").(string),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetDeadLetter.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
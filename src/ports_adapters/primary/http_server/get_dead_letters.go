@@ -0,0 +1,44 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetDeadLetters получает страницу DLQ очереди
+// @Summary Получение DLQ очереди
+// @Description Возвращает страницу задач очереди, перенесенных в DLQ после окончательной ошибки выполнения.
+// @Description Следующая страница запрашивается с cursor из поля nextCursor ответа и тем же sort.
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param limit query int false "Размер страницы (1-1000, по умолчанию 100)"
+// @Param cursor query string false "Курсор следующей страницы (nextCursor из предыдущего ответа)"
+// @Param sort query string false "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)"
+// @Success 200 {object} dto.Response{data=[]domain.Task} "Задачи DLQ получены"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue} [get]
+func (s Server) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	queue := r.Context().Value("queue").(string)
+	query := r.URL.Query()
+	req := dto.GetDeadLettersRequest{
+		Queue:  queue,
+		Limit:  query.Get("limit"),
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetDeadLetters.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	responsePage(w, res.Tasks, res.NextCursor)
+
+}
@@ -0,0 +1,25 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetRetention получает правила хранения задач
+// @Summary Получение правил хранения задач
+// @Description Возвращает текущие правила хранения задач и количество удаленных по ним задач
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.Response{data=domain.Retention} "Правила хранения получены"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /admin/retention [get]
+func (s Server) GetRetention(w http.ResponseWriter, r *http.Request) {
+	res, err := s.app.Query.GetRetention.Handle(r.Context(), dto.GetRetentionRequest{})
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
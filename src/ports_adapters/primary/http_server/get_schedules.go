@@ -0,0 +1,25 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetSchedules получает список расписаний
+// @Summary Получение списка расписаний
+// @Description Возвращает все расписания с временем последнего и следующего срабатывания
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.Response{data=[]domain.Schedule} "Список расписаний получен"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /schedule [get]
+func (s Server) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	res, err := s.app.Query.GetSchedules.Handle(r.Context(), dto.GetSchedulesRequest{})
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
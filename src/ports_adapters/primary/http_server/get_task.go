@@ -13,7 +13,9 @@ import (
 // @Produce json
 // @Param id path string true "ID задачи"
 // @Success 200 {object} dto.Response{data=domain.Task} "Задача найдена"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
 // @Failure 400 {object} dto.Response "Некорректный ID задачи"
+// @Failure 404 {object} dto.Response "Задача не найдена"
 // @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
 // @Router /task/{id} [get]
 func (s Server) GetTaskForId(w http.ResponseWriter, r *http.Request) {
@@ -28,9 +30,10 @@ func (s Server) GetTaskForId(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := s.app.Query.GetTask.Handle(r.Context(), req)
 	if err != nil {
-		response(w, nil, http.StatusInternalServerError, err)
+		response(w, nil, errorStatus(err), err)
 		return
 	}
+	setETag(w, res)
 	response(w, res, http.StatusOK, nil)
 
 }
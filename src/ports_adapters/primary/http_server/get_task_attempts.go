@@ -0,0 +1,37 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetTaskAttempts получает историю попыток выполнения задачи
+// @Summary Получение истории попыток задачи
+// @Description Возвращает завершенные попытки выполнения задачи от первой к последней: воркер, время начала и завершения, итог, ошибку и длительность. Хранятся только последние 20 попыток
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=[]domain.TaskAttempt} "История попыток получена"
+// @Failure 400 {object} dto.Response "Некорректный ID задачи"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/attempts [get]
+func (s Server) GetTaskAttempts(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	req := dto.GetTaskAttemptsRequest{
+		ID: id,
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetTaskAttempts.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,36 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetTaskAudit получает журнал аудита задачи
+// @Summary Получение журнала аудита задачи
+// @Description Возвращает все команды, выполненные над задачей, в порядке выполнения: инициатор, команда, статус до и после, ID запроса. Журнал доступен и после удаления задачи
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=[]domain.AuditEntry} "Журнал аудита получен"
+// @Failure 400 {object} dto.Response "Некорректный ID задачи"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/audit [get]
+func (s Server) GetTaskAudit(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	req := dto.GetTaskAuditRequest{
+		ID: id,
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetTaskAudit.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,37 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// GetTaskChildren получает дочерние задачи
+// @Summary Получение дочерних задач
+// @Description Возвращает прямых потомков задачи в порядке создания
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=[]domain.Task} "Дочерние задачи получены"
+// @Failure 400 {object} dto.Response "Некорректный ID задачи"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/children [get]
+func (s Server) GetTaskChildren(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	req := dto.GetTaskChildrenRequest{
+		ID: id,
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.GetTaskChildren.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
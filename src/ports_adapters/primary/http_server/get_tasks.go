@@ -5,21 +5,53 @@ import (
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
 )
 
-// GetTasksSortStatus получает список задач с фильтрацией по статусу
+// GetTasksSortStatus получает страницу списка задач с фильтрацией по полям задачи
 // @Summary Получение списка задач
-// @Description Возвращает список задач с возможностью фильтрации по статусу
+// @Description Возвращает страницу списка задач с фильтрацией и сортировкой. Все заданные фильтры объединяются по И.
+// @Description При равенстве поля сортировки задачи упорядочиваются по createdAt, затем по ID.
+// @Description Следующая страница запрашивается с cursor из поля nextCursor ответа и тем же sort.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param status query string false "Статус для фильтрации (pending, processing, completed, failed, retrying)"
+// @Param status query string false "Статус для фильтрации (blocked, scheduled, pending, processing, completed, failed, retrying, cancelled)"
+// @Param queue query string false "Очередь"
+// @Param type query string false "Тип задачи"
+// @Param priority query string false "Приоритет (low, medium, high, critical)"
+// @Param workerId query string false "ID воркера, выполняющего задачу"
+// @Param parentId query string false "ID родительской задачи"
+// @Param createdFrom query string false "Создана не раньше (RFC3339)"
+// @Param createdTo query string false "Создана не позже (RFC3339)"
+// @Param updatedFrom query string false "Изменена не раньше (RFC3339)"
+// @Param updatedTo query string false "Изменена не позже (RFC3339)"
+// @Param minRetries query int false "Минимальное количество попыток"
+// @Param maxRetries query int false "Максимальное количество попыток"
+// @Param metadata query []string false "Условие на метаданные ключ:значение; можно указать несколько" collectionFormat(multi)
+// @Param limit query int false "Размер страницы (1-1000, по умолчанию 100)"
+// @Param cursor query string false "Курсор следующей страницы (nextCursor из предыдущего ответа)"
+// @Param sort query string false "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)"
 // @Success 200 {object} dto.Response{data=[]domain.Task} "Список задач получен"
 // @Failure 400 {object} dto.Response "Некорректные параметры запроса"
 // @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
 // @Router /task [get]
 func (s Server) GetTasksSortStatus(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
+	query := r.URL.Query()
 	req := dto.GetTaskWhithFiltersRequest{
-		Status: status,
+		Status:      query.Get("status"),
+		Queue:       query.Get("queue"),
+		Type:        query.Get("type"),
+		Priority:    query.Get("priority"),
+		WorkerID:    query.Get("workerId"),
+		ParentID:    query.Get("parentId"),
+		CreatedFrom: query.Get("createdFrom"),
+		CreatedTo:   query.Get("createdTo"),
+		UpdatedFrom: query.Get("updatedFrom"),
+		UpdatedTo:   query.Get("updatedTo"),
+		MinRetries:  query.Get("minRetries"),
+		MaxRetries:  query.Get("maxRetries"),
+		Metadata:    query["metadata"],
+		Limit:       query.Get("limit"),
+		Cursor:      query.Get("cursor"),
+		Sort:        query.Get("sort"),
 	}
 	err := req.Validate()
 	if err != nil {
@@ -28,9 +60,9 @@ func (s Server) GetTasksSortStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := s.app.Query.GetTasks.Handle(r.Context(), req)
 	if err != nil {
-		response(w, nil, http.StatusInternalServerError, err)
+		response(w, nil, errorStatus(err), err)
 		return
 	}
-	response(w, res, http.StatusOK, nil)
+	responsePage(w, res.Tasks, res.NextCursor)
 
 }
@@ -0,0 +1,48 @@
+package http_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// HeartbeatTask продлевает аренду задачи воркером
+// @Summary Продление аренды задачи
+// @Description Продлевает аренду задачи, если она находится в processing и удерживается указанным воркером.
+// @Description Если для задачи запрошена отмена, в ответе cancelRequested=true: воркер должен прекратить выполнение и перевести задачу в cancelled.
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param id path string true "ID задачи"
+// @Param heartbeat body dto.HeartbeatRequest true "Данные воркера"
+// @Success 200 {object} dto.Response{data=domain.Task} "Аренда продлена"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 409 {object} dto.Response "Задача не удерживается воркером"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/{id}/heartbeat [post]
+func (s Server) HeartbeatTask(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value("id").(string)
+	var req dto.HeartbeatRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Id = id
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.HeartbeatTask.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
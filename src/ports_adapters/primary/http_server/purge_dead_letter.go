@@ -0,0 +1,38 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// PurgeDeadLetter удаляет задачу из DLQ очереди
+// @Summary Удаление задачи из DLQ
+// @Description Безвозвратно удаляет задачу из DLQ очереди, минуя архив
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача удалена"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 404 {object} dto.Response "Задачи нет в DLQ очереди"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue}/task/{id} [delete]
+func (s Server) PurgeDeadLetter(w http.ResponseWriter, r *http.Request) {
+	req := dto.DeadLetterRequest{
+		Queue: r.Context().Value("queue").(string),
+		Id:    r.Context().Value("id").(string),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.PurgeDeadLetter.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,45 @@
+package http_server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// PurgeDeadLetters обрабатывает задачи DLQ очереди
+// @Summary Очистка DLQ
+// @Description Безвозвратно удаляет задачи из DLQ очереди, минуя архив. Без ids удаляются все задачи DLQ очереди; задачи, которых нет в DLQ очереди, пропускаются
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param tasks body dto.DeadLettersRequest false "ID задач"
+// @Success 200 {object} dto.Response{data=[]string} "ID удаленных задач"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue} [delete]
+func (s Server) PurgeDeadLetters(w http.ResponseWriter, r *http.Request) {
+	queue := r.Context().Value("queue").(string)
+	var req dto.DeadLettersRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Queue = queue
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.PurgeDeadLetters.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
@@ -0,0 +1,40 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// RequeueDeadLetter возвращает задачу из DLQ в очередь
+// @Summary Повтор задачи из DLQ
+// @Description Переводит задачу из DLQ очереди в pending со сброшенным retryCount. lastError и история попыток сохраняются
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param id path string true "ID задачи"
+// @Success 200 {object} dto.Response{data=domain.Task} "Задача возвращена в очередь"
+// @Header 200 {string} ETag "Версия задачи для If-Match"
+// @Failure 400 {object} dto.Response "Некорректные параметры запроса"
+// @Failure 404 {object} dto.Response "Задачи нет в DLQ очереди"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue}/task/{id}/requeue [post]
+func (s Server) RequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	req := dto.DeadLetterRequest{
+		Queue: r.Context().Value("queue").(string),
+		Id:    r.Context().Value("id").(string),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.RequeueDeadLetter.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	setETag(w, res)
+	response(w, res, http.StatusOK, nil)
+
+}
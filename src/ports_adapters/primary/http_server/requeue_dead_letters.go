@@ -0,0 +1,45 @@
+package http_server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// RequeueDeadLetters обрабатывает задачи DLQ очереди
+// @Summary Массовый повтор задач из DLQ
+// @Description Переводит задачи из DLQ очереди в pending со сброшенным retryCount. Без ids в DLQ возвращаются все задачи очереди; задачи, которых нет в DLQ очереди, пропускаются
+// @Tags dlq
+// @Accept json
+// @Produce json
+// @Param queue path string true "Имя очереди"
+// @Param tasks body dto.DeadLettersRequest false "ID задач"
+// @Success 200 {object} dto.Response{data=[]string} "ID возвращенных в очередь задач"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /dlq/{queue}/requeue [post]
+func (s Server) RequeueDeadLetters(w http.ResponseWriter, r *http.Request) {
+	queue := r.Context().Value("queue").(string)
+	var req dto.DeadLettersRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	req.Queue = queue
+
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.RequeueDeadLetters.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
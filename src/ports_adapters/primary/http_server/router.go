@@ -26,6 +26,10 @@ func (r *Router) POST(path string, handler http.HandlerFunc) {
 	r.Handle("POST", path, handler)
 }
 
+func (r *Router) DELETE(path string, handler http.HandlerFunc) {
+	r.Handle("DELETE", path, handler)
+}
+
 func (r *Router) Handle(method, path string, handler http.Handler) {
 	if r.routes[path] == nil {
 		r.routes[path] = make(map[string]http.Handler)
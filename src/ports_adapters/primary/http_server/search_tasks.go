@@ -0,0 +1,45 @@
+package http_server
+
+import (
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// SearchTasks ищет задачи по выражению на языке запросов
+// @Summary Поиск задач
+// @Description Возвращает страницу задач, удовлетворяющих выражению, например
+// @Description queue = "billing" AND priority IN ("high","critical") AND metadata.user_id = "123" AND retryCount > 2.
+// @Description Операторы: =, !=, <, <=, >, >=, IN, NOT IN; выражения объединяются AND, OR, NOT и скобками.
+// @Description Ошибка разбора возвращается с позицией символа, на котором она обнаружена.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param q query string true "Выражение поиска"
+// @Param limit query int false "Размер страницы (1-1000, по умолчанию 100)"
+// @Param cursor query string false "Курсор следующей страницы (nextCursor из предыдущего ответа)"
+// @Param sort query string false "Порядок: createdAt, updatedAt, priority, scheduledAt с суффиксом :asc или :desc (по умолчанию priority:desc)"
+// @Success 200 {object} dto.Response{data=[]domain.Task} "Найденные задачи"
+// @Failure 400 {object} dto.Response "Некорректное выражение или параметры запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /task/search [get]
+func (s Server) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := dto.SearchTasksRequest{
+		Q:      query.Get("q"),
+		Limit:  query.Get("limit"),
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+	err := req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Query.SearchTasks.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	responsePage(w, res.Tasks, res.NextCursor)
+
+}
@@ -2,8 +2,11 @@ package http_server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"svc-task_master/src/application"
+	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/primary/http_server/dto"
 )
 
@@ -33,3 +36,66 @@ func response(w http.ResponseWriter, data any, status int, err error) {
 	w.WriteHeader(status)
 	w.Write(body)
 }
+
+// setETag передает версию задачи в заголовке ETag; вызывается до записи ответа
+func setETag(w http.ResponseWriter, task domain.Task) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(task.Version, 10)))
+}
+
+// responseCreated отправляет ID созданной задачи. Если запрос повторен с уже
+// использованным ключом идемпотентности, ответ отмечается флагом replayed
+// и заголовком Idempotent-Replayed
+func responseCreated(w http.ResponseWriter, created domain.CreatedTask) {
+	res := dto.Response{
+		Status:   http.StatusOK,
+		Data:     created.ID,
+		Replayed: created.Replayed,
+	}
+	body, _ := json.Marshal(res)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if created.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// responsePage отправляет страницу списка вместе с курсором следующей страницы
+func responsePage(w http.ResponseWriter, data any, nextCursor string) {
+	res := dto.Response{
+		Status:     http.StatusOK,
+		Data:       data,
+		NextCursor: nextCursor,
+	}
+	body, _ := json.Marshal(res)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrDependencyNotFound),
+		errors.Is(err, domain.ErrDependencyFailed),
+		errors.Is(err, domain.ErrParentTaskNotFound),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrTaskNotFound),
+		errors.Is(err, domain.ErrNoTaskAvailable),
+		errors.Is(err, domain.ErrScheduleNotFound),
+		errors.Is(err, domain.ErrArchivedTaskNotFound),
+		errors.Is(err, domain.ErrArchiveDisabled),
+		errors.Is(err, domain.ErrDeadLetterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrLeaseNotHeld):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,39 @@
+package http_server
+
+import (
+	"encoding/json"
+	"net/http"
+	"svc-task_master/src/ports_adapters/primary/http_server/dto"
+)
+
+// UpdateRetention заменяет правила хранения задач
+// @Summary Изменение правил хранения задач
+// @Description Заменяет правила хранения задач; новые правила применяются при следующей проверке
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param retention body dto.UpdateRetentionRequest true "Правила хранения"
+// @Success 200 {object} dto.Response{data=domain.Retention} "Правила хранения изменены"
+// @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
+// @Router /admin/retention [put]
+func (s Server) UpdateRetention(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateRetentionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	err = req.Validate()
+	if err != nil {
+		response(w, nil, http.StatusBadRequest, err)
+		return
+	}
+	res, err := s.app.Command.UpdateRetention.Handle(r.Context(), req)
+	if err != nil {
+		response(w, nil, errorStatus(err), err)
+		return
+	}
+	response(w, res, http.StatusOK, nil)
+
+}
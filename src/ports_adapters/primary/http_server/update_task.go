@@ -8,14 +8,20 @@ import (
 
 // UpdateStatusTask обновляет статус задачи
 // @Summary Обновление статуса задачи
-// @Description Обновляет статус задачи по указанному идентификатору
+// @Description Обновляет статус задачи по указанному идентификатору. Если передан заголовок If-Match
+// @Description с ETag задачи, обновление выполняется, только пока версия задачи не изменилась
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path string true "ID задачи"
+// @Param If-Match header string false "ETag задачи, полученный при чтении"
 // @Param task body dto.UpdateTaskStatusRequest true "Данные для обновления статуса"
 // @Success 200 {object} dto.Response{data=domain.Task} "Статус задачи обновлен"
+// @Header 200 {string} ETag "Версия задачи после обновления"
 // @Failure 400 {object} dto.Response "Некорректные данные запроса"
+// @Failure 404 {object} dto.Response "Задача не найдена"
+// @Failure 409 {object} dto.Response "Недопустимый переход статуса"
+// @Failure 412 {object} dto.Response "Версия задачи не совпадает с If-Match"
 // @Failure 500 {object} dto.Response "Внутренняя ошибка сервера"
 // @Router /task/{id} [put]
 func (s Server) UpdateStatusTask(w http.ResponseWriter, r *http.Request) {
@@ -24,8 +30,10 @@ func (s Server) UpdateStatusTask(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		response(w, nil, http.StatusBadRequest, err)
+		return
 	}
 	req.Id = id
+	req.IfMatch = r.Header.Get("If-Match")
 
 	err = req.Validate()
 	if err != nil {
@@ -34,9 +42,10 @@ func (s Server) UpdateStatusTask(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := s.app.Command.UpdateTask.Handle(r.Context(), req)
 	if err != nil {
-		response(w, nil, http.StatusInternalServerError, err)
+		response(w, nil, errorStatus(err), err)
 		return
 	}
+	setETag(w, res)
 	response(w, res, http.StatusOK, nil)
 
 }
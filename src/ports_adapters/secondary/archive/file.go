@@ -0,0 +1,281 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+const (
+	filePrefix = "archive-"
+	fileSuffix = ".jsonl.gz"
+	dayLayout  = "2006-01-02"
+)
+
+// FileArchive хранит архивные задачи в сжатых gzip файлах JSON Lines, по файлу на день (UTC).
+// Каждая пачка задач дописывается в файл отдельным gzip-потоком, поэтому файл остается
+// читаемым целиком, а недописанный при аварийной остановке хвост отбрасывается при чтении.
+// В памяти хранится индекс дня последней архивной копии каждой задачи, поэтому Get
+// читает один файл, а не весь архив
+type FileArchive struct {
+	mu            sync.Mutex
+	logger        domain.ILogger
+	dir           string
+	retentionDays int
+	index         map[string]string
+}
+
+var _ domain.ITaskArchive = &FileArchive{}
+
+func NewFileArchive(dir string, retentionDays int, logger domain.ILogger) (*FileArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	a := &FileArchive{
+		logger:        logger,
+		dir:           dir,
+		retentionDays: retentionDays,
+		index:         make(map[string]string),
+	}
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// load строит индекс по файлам архива; файлы читаются от ранних к поздним,
+// поэтому для задачи остается день ее последней архивной копии
+func (a *FileArchive) load() error {
+	days, err := a.days()
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		err := a.scan(context.Background(), day, func(task domain.ArchivedTask) {
+			a.index[task.Task.ID] = day
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.logger.Info("Loaded task archive index",
+		slog.Attr{Key: "files", Value: slog.IntValue(len(days))},
+		slog.Attr{Key: "tasks", Value: slog.IntValue(len(a.index))},
+	)
+	return nil
+}
+
+func (a *FileArchive) path(day string) string {
+	return filepath.Join(a.dir, filePrefix+day+fileSuffix)
+}
+
+func (a *FileArchive) Archive(tasks []domain.Task, at time.Time) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	day := at.UTC().Format(dayLayout)
+	file, err := os.OpenFile(a.path(day), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zw := gzip.NewWriter(file)
+	encoder := json.NewEncoder(zw)
+	for _, task := range tasks {
+		if err := encoder.Encode(domain.ArchivedTask{Task: task, ArchivedAt: at}); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		a.index[task.ID] = day
+	}
+
+	a.prune(at)
+	return nil
+}
+
+// prune удаляет файлы старше retentionDays дней
+func (a *FileArchive) prune(now time.Time) {
+	if a.retentionDays <= 0 {
+		return
+	}
+	cutoff := now.UTC().AddDate(0, 0, -a.retentionDays).Format(dayLayout)
+	days, err := a.days()
+	if err != nil {
+		a.logger.Error("Failed to list archive files", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+		return
+	}
+	removed := make(map[string]bool)
+	for _, day := range days {
+		if day >= cutoff {
+			break
+		}
+		if err := os.Remove(a.path(day)); err != nil {
+			a.logger.Error("Failed to remove archive file",
+				slog.Attr{Key: "file", Value: slog.StringValue(a.path(day))},
+				slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+			)
+			continue
+		}
+		removed[day] = true
+	}
+	if len(removed) == 0 {
+		return
+	}
+	for id, day := range a.index {
+		if removed[day] {
+			delete(a.index, id)
+		}
+	}
+}
+
+// days возвращает отсортированные дни, за которые есть файлы архива
+func (a *FileArchive) days() ([]string, error) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+	var days []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		day := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+		if _, err := time.Parse(dayLayout, day); err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+// scan вызывает fn для каждой записи файла архива за день
+func (a *FileArchive) scan(ctx context.Context, day string, fn func(task domain.ArchivedTask)) error {
+	file, err := os.Open(a.path(day))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	defer zr.Close()
+
+	decoder := json.NewDecoder(zr)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var task domain.ArchivedTask
+		err := decoder.Decode(&task)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			a.logger.Warn("Archive file is truncated, skipping tail",
+				slog.Attr{Key: "file", Value: slog.StringValue(a.path(day))},
+				slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+			)
+			return nil
+		}
+		fn(task)
+	}
+}
+
+func (a *FileArchive) Get(ctx context.Context, id string) (domain.ArchivedTask, error) {
+	a.mu.Lock()
+	day, ok := a.index[id]
+	a.mu.Unlock()
+	if !ok {
+		return domain.ArchivedTask{}, domain.ErrArchivedTaskNotFound
+	}
+
+	var (
+		found  domain.ArchivedTask
+		exists bool
+	)
+	err := a.scan(ctx, day, func(task domain.ArchivedTask) {
+		if task.Task.ID == id {
+			found, exists = task, true
+		}
+	})
+	if err != nil {
+		return domain.ArchivedTask{}, err
+	}
+	if !exists {
+		// Файл удален по сроку хранения после чтения индекса
+		return domain.ArchivedTask{}, domain.ErrArchivedTaskNotFound
+	}
+	return found, nil
+}
+
+func (a *FileArchive) Find(ctx context.Context, from, to time.Time, limit int) ([]domain.ArchivedTask, error) {
+	days, err := a.days()
+	if err != nil {
+		return nil, err
+	}
+
+	// Задача архивируется не раньше своего последнего изменения, поэтому файлы
+	// за дни до from можно не читать
+	fromDay := ""
+	if !from.IsZero() {
+		fromDay = from.UTC().Format(dayLayout)
+	}
+
+	var result []domain.ArchivedTask
+	for _, day := range days {
+		if day < fromDay {
+			continue
+		}
+		err := a.scan(ctx, day, func(task domain.ArchivedTask) {
+			updatedAt := task.Task.UpdatedAt
+			if (!from.IsZero() && updatedAt.Before(from)) || (!to.IsZero() && updatedAt.After(to)) {
+				return
+			}
+			result = append(result, task)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Task.UpdatedAt.Before(result[j].Task.UpdatedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"testing"
+	"time"
+)
+
+func TestFileArchiveGetUsesLatestCopy(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	archive, err := NewFileArchive(dir, 0, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("NewFileArchive: %v", err)
+	}
+
+	day1 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	if err := archive.Archive([]domain.Task{{ID: "a", Status: domain.TaskStatusFailed}, {ID: "b"}}, day1); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if err := archive.Archive([]domain.Task{{ID: "a", Status: domain.TaskStatusCompleted}}, day2); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	check := func(archive *FileArchive) {
+		t.Helper()
+		got, err := archive.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get(a): %v", err)
+		}
+		if got.Task.Status != domain.TaskStatusCompleted || !got.ArchivedAt.Equal(day2) {
+			t.Fatalf("Get(a) = %s archived at %s, want the latest copy", got.Task.Status, got.ArchivedAt)
+		}
+		if _, err := archive.Get(ctx, "b"); err != nil {
+			t.Fatalf("Get(b): %v", err)
+		}
+		if _, err := archive.Get(ctx, "missing"); !errors.Is(err, domain.ErrArchivedTaskNotFound) {
+			t.Fatalf("Get(missing): %v, want ErrArchivedTaskNotFound", err)
+		}
+	}
+	check(archive)
+
+	reopened, err := NewFileArchive(dir, 0, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("NewFileArchive: %v", err)
+	}
+	check(reopened)
+
+	// Файл за первый день удален, индекс продолжает указывать на него
+	if err := os.Remove(reopened.path(day1.Format(dayLayout))); err != nil {
+		t.Fatalf("remove archive file: %v", err)
+	}
+	if _, err := reopened.Get(ctx, "b"); !errors.Is(err, domain.ErrArchivedTaskNotFound) {
+		t.Fatalf("Get(b) after its file is removed: %v, want ErrArchivedTaskNotFound", err)
+	}
+}
+
+func TestFileArchivePruneDropsIndexEntries(t *testing.T) {
+	ctx := context.Background()
+	archive, err := NewFileArchive(t.TempDir(), 1, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("NewFileArchive: %v", err)
+	}
+
+	old := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := archive.Archive([]domain.Task{{ID: "old"}}, old); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if err := archive.Archive([]domain.Task{{ID: "new"}}, old.AddDate(0, 0, 5)); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if _, ok := archive.index["old"]; ok {
+		t.Fatal("pruned task is still indexed")
+	}
+	if _, err := archive.Get(ctx, "old"); !errors.Is(err, domain.ErrArchivedTaskNotFound) {
+		t.Fatalf("Get(old): %v, want ErrArchivedTaskNotFound", err)
+	}
+	if _, err := archive.Get(ctx, "new"); err != nil {
+		t.Fatalf("Get(new): %v", err)
+	}
+}
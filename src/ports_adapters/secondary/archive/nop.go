@@ -0,0 +1,24 @@
+package archive
+
+import (
+	"context"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+// Nop архив, который ничего не сохраняет: задачи удаляются безвозвратно
+type Nop struct{}
+
+var _ domain.ITaskArchive = Nop{}
+
+func (Nop) Archive(tasks []domain.Task, at time.Time) error {
+	return nil
+}
+
+func (Nop) Get(ctx context.Context, id string) (domain.ArchivedTask, error) {
+	return domain.ArchivedTask{}, domain.ErrArchiveDisabled
+}
+
+func (Nop) Find(ctx context.Context, from, to time.Time, limit int) ([]domain.ArchivedTask, error) {
+	return nil, domain.ErrArchiveDisabled
+}
@@ -0,0 +1,250 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"svc-task_master/src/domain"
+	"sync"
+)
+
+const fileName = "audit.jsonl"
+
+// Log журнал аудита. Если задан каталог, записи дописываются в файл JSON Lines,
+// а в памяти держится только индекс смещений записей по задачам; поиск читает записи
+// из файла. Файл только дописывается, каждая запись сбрасывается на диск до возврата
+// из Append. Без каталога записи хранятся в памяти
+type Log struct {
+	mu     sync.RWMutex
+	logger domain.ILogger
+	file   *os.File
+	// size длина файла: байты до нее уже не меняются и читаются без блокировки
+	size    int64
+	lastSeq int64
+	// byTask смещения записей задачи в файле или номера записей в entries
+	byTask  map[string][]int64
+	entries []domain.AuditEntry
+}
+
+var _ domain.IAuditRepository = &Log{}
+
+// NewLog открывает журнал в каталоге dir; пустой dir — журнал только в памяти
+func NewLog(dir string, logger domain.ILogger) (*Log, error) {
+	l := &Log{
+		logger: logger,
+		byTask: make(map[string][]int64),
+	}
+	if dir == "" {
+		return l, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fileName)
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l.file = file
+	return l, nil
+}
+
+// load строит индекс по файлу журнала. Недописанная при аварийной остановке
+// последняя строка отбрасывается и обрезается, чтобы новые записи не склеились с ней
+func (l *Log) load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var entry domain.AuditEntry
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr == nil {
+				l.index(entry, l.size)
+				l.size += int64(len(line))
+				continue
+			}
+		}
+		if len(line) > 0 {
+			l.logger.Warn("Audit log is truncated, skipping tail",
+				slog.Attr{Key: "file", Value: slog.StringValue(path)},
+				slog.Attr{Key: "offset", Value: slog.Int64Value(l.size)},
+			)
+			return os.Truncate(path, l.size)
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// index добавляет запись в индекс; position — смещение записи в файле
+// или ее номер в entries
+func (l *Log) index(entry domain.AuditEntry, position int64) {
+	if entry.TaskID != "" {
+		l.byTask[entry.TaskID] = append(l.byTask[entry.TaskID], position)
+	}
+	l.lastSeq = entry.Seq
+}
+
+func (l *Log) Append(entry domain.AuditEntry) (domain.AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.lastSeq + 1
+
+	if l.file == nil {
+		l.index(entry, int64(len(l.entries)))
+		l.entries = append(l.entries, entry)
+		return entry, nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+	line = append(line, '\n')
+	if _, err := l.file.Write(line); err != nil {
+		l.truncate()
+		return domain.AuditEntry{}, err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.truncate()
+		return domain.AuditEntry{}, err
+	}
+
+	l.index(entry, l.size)
+	l.size += int64(len(line))
+	return entry, nil
+}
+
+// truncate отбрасывает недописанную запись, чтобы следующая не склеилась с ней
+func (l *Log) truncate() {
+	if err := l.file.Truncate(l.size); err != nil {
+		l.logger.Error("Failed to truncate audit log",
+			slog.Attr{Key: "offset", Value: slog.Int64Value(l.size)},
+			slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+		)
+	}
+}
+
+func (l *Log) Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	result := []domain.AuditEntry{}
+	visit := func(entry domain.AuditEntry) bool {
+		if filter.Match(entry) {
+			result = append(result, entry)
+		}
+		return filter.Limit <= 0 || len(result) < filter.Limit
+	}
+
+	if l.file == nil {
+		return result, l.findInMemory(ctx, filter, visit)
+	}
+
+	// Записи до size неизменны, поэтому файл читается без блокировки журнала
+	l.mu.RLock()
+	size := l.size
+	offsets := l.byTask[filter.TaskID]
+	l.mu.RUnlock()
+
+	if filter.TaskID != "" {
+		section := io.NewSectionReader(l.file, 0, size)
+		for _, offset := range offsets {
+			if _, err := section.Seek(offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+			entry, err := readEntry(bufio.NewReader(section))
+			if err != nil {
+				return nil, err
+			}
+			if !visit(entry) {
+				break
+			}
+		}
+		return result, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(l.file, 0, size))
+	for i := 0; ; i++ {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		entry, err := readEntry(reader)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !visit(entry) {
+			return result, nil
+		}
+	}
+}
+
+func (l *Log) findInMemory(ctx context.Context, filter domain.AuditFilter, visit func(domain.AuditEntry) bool) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if filter.TaskID != "" {
+		for _, i := range l.byTask[filter.TaskID] {
+			if !visit(l.entries[i]) {
+				break
+			}
+		}
+		return nil
+	}
+
+	for i, entry := range l.entries {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if !visit(entry) {
+			break
+		}
+	}
+	return nil
+}
+
+// readEntry читает очередную строку журнала
+func readEntry(reader *bufio.Reader) (domain.AuditEntry, error) {
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(line) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		return domain.AuditEntry{}, err
+	}
+	var entry domain.AuditEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return domain.AuditEntry{}, err
+	}
+	return entry, nil
+}
+
+func (l *Log) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"testing"
+)
+
+func TestLogFindReadsEntriesFromFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	log, err := NewLog(dir, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("NewLog: %v", err)
+	}
+	for _, entry := range []domain.AuditEntry{
+		{Actor: "alice", Command: "CreateTask", TaskID: "a"},
+		{Actor: "bob", Command: "CreateTask", TaskID: "b"},
+		{Actor: "alice", Command: "CancelTask", TaskID: "a"},
+		{Actor: domain.AuditActorSystem, Command: "FireSchedules"},
+	} {
+		if _, err := log.Append(entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Недописанная при аварии строка отбрасывается при открытии
+	path := filepath.Join(dir, fileName)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open audit file: %v", err)
+	}
+	if _, err := file.WriteString(`{"seq":5,"actor":"tor`); err != nil {
+		t.Fatalf("write torn entry: %v", err)
+	}
+	file.Close()
+
+	log, err = NewLog(dir, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("NewLog: %v", err)
+	}
+	defer log.Close()
+	entry, err := log.Append(domain.AuditEntry{Actor: "bob", Command: "CompleteTask", TaskID: "a"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if entry.Seq != 5 {
+		t.Fatalf("Seq after reopen = %d, want 5", entry.Seq)
+	}
+
+	seqs := func(t *testing.T, filter domain.AuditFilter) []int64 {
+		t.Helper()
+		entries, err := log.Find(ctx, filter)
+		if err != nil {
+			t.Fatalf("Find(%+v): %v", filter, err)
+		}
+		result := []int64{}
+		for _, entry := range entries {
+			result = append(result, entry.Seq)
+		}
+		return result
+	}
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   []int64
+	}{
+		{"all", domain.AuditFilter{}, []int64{1, 2, 3, 4, 5}},
+		{"task", domain.AuditFilter{TaskID: "a"}, []int64{1, 3, 5}},
+		{"task and actor", domain.AuditFilter{TaskID: "a", Actor: "bob"}, []int64{5}},
+		{"task with limit", domain.AuditFilter{TaskID: "a", Limit: 2}, []int64{1, 3}},
+		{"actor", domain.AuditFilter{Actor: "alice"}, []int64{1, 3}},
+		{"unknown task", domain.AuditFilter{TaskID: "missing"}, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := seqs(t, tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Find = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Find = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,588 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"testing"
+	"time"
+)
+
+// TaskStorage хранилище задач, которое проверяет контракт
+type TaskStorage interface {
+	domain.IInMemoRepository
+	io.Closer
+}
+
+// OpenTaskStorage открывает хранилище задач в каталоге dir; повторное открытие
+// того же каталога после Close должно восстановить сохраненные задачи
+type OpenTaskStorage func(t *testing.T, dir string) TaskStorage
+
+// NopLogger логгер, отбрасывающий все записи
+type NopLogger struct{}
+
+func (NopLogger) Info(string, ...slog.Attr)  {}
+func (NopLogger) Error(string, ...slog.Attr) {}
+func (NopLogger) Debug(string, ...slog.Attr) {}
+func (NopLogger) Warn(string, ...slog.Attr)  {}
+
+var baseTime = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// RunTaskRepository проверяет, что хранилище задач выполняет контракт
+// domain.IInMemoRepository, на котором построены команды и запросы
+func RunTaskRepository(t *testing.T, open OpenTaskStorage) {
+	t.Run("Get", func(t *testing.T) { testGet(t, openClosed(t, open)) })
+	t.Run("SetUpdate", func(t *testing.T) { testSetUpdate(t, openClosed(t, open)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, openClosed(t, open)) })
+	t.Run("UpdateStatus", func(t *testing.T) { testUpdateStatus(t, openClosed(t, open)) })
+	t.Run("CompareAndSwap", func(t *testing.T) { testCompareAndSwap(t, openClosed(t, open)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, openClosed(t, open)) })
+	t.Run("ClaimNext", func(t *testing.T) { testClaimNext(t, openClosed(t, open)) })
+	t.Run("GetAllFilterStatus", func(t *testing.T) { testGetAllFilterStatus(t, openClosed(t, open)) })
+	t.Run("ListTasks", func(t *testing.T) { testListTasks(t, openClosed(t, open)) })
+	t.Run("GetChildren", func(t *testing.T) { testGetChildren(t, openClosed(t, open)) })
+	t.Run("GetDependents", func(t *testing.T) { testGetDependents(t, openClosed(t, open)) })
+	t.Run("Reopen", func(t *testing.T) { testReopen(t, open) })
+}
+
+// openClosed открывает хранилище в новом каталоге и закрывает его по окончании теста
+func openClosed(t *testing.T, open OpenTaskStorage) TaskStorage {
+	t.Helper()
+	storage := open(t, t.TempDir())
+	t.Cleanup(func() {
+		if err := storage.Close(); err != nil {
+			t.Errorf("close storage: %v", err)
+		}
+	})
+	return storage
+}
+
+func newTask(id string, queue string, priority domain.TaskPriority, createdAt time.Time) domain.Task {
+	return domain.Task{
+		ID:        id,
+		Type:      "email",
+		Queue:     queue,
+		Status:    domain.TaskStatusPending,
+		Priority:  priority,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Payload:   map[string]interface{}{"to": id},
+	}
+}
+
+func put(t *testing.T, storage TaskStorage, tasks ...domain.Task) {
+	t.Helper()
+	for _, task := range tasks {
+		if err := storage.SetUpdate(task.ID, task); err != nil {
+			t.Fatalf("SetUpdate(%s): %v", task.ID, err)
+		}
+	}
+}
+
+func mustGet(t *testing.T, storage TaskStorage, id string) domain.Task {
+	t.Helper()
+	task, ok := storage.Get(id)
+	if !ok {
+		t.Fatalf("Get(%s): task not found", id)
+	}
+	return task
+}
+
+func ids(tasks []domain.Task) []string {
+	result := make([]string, len(tasks))
+	for i, task := range tasks {
+		result[i] = task.ID
+	}
+	return result
+}
+
+func assertIDs(t *testing.T, name string, tasks []domain.Task, want ...string) {
+	t.Helper()
+	got := ids(tasks)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+// assertSameIDs сравнивает наборы задач без учета порядка
+func assertSameIDs(t *testing.T, name string, tasks []domain.Task, want ...string) {
+	t.Helper()
+	got := make(map[string]int)
+	for _, id := range ids(tasks) {
+		got[id]++
+	}
+	for _, id := range want {
+		got[id]--
+	}
+	for id, count := range got {
+		if count != 0 {
+			t.Fatalf("%s: got %v, want %v (mismatch on %s)", name, ids(tasks), want, id)
+		}
+	}
+}
+
+func testGet(t *testing.T, storage TaskStorage) {
+	if _, ok := storage.Get("missing"); ok {
+		t.Fatal("Get of a missing task reported ok")
+	}
+
+	task := newTask("a", "default", domain.TaskPriorityHigh, baseTime)
+	task.ParentTaskID = "parent"
+	task.DependsOn = []string{"dep"}
+	put(t, storage, task)
+
+	got := mustGet(t, storage, "a")
+	if got.ID != "a" || got.Queue != "default" || got.Priority != domain.TaskPriorityHigh || got.Status != domain.TaskStatusPending {
+		t.Fatalf("Get returned %+v", got)
+	}
+	if !got.CreatedAt.Equal(baseTime) || got.ParentTaskID != "parent" || !got.DependsOnTask("dep") {
+		t.Fatalf("Get returned %+v", got)
+	}
+	if got.Payload["to"] != "a" {
+		t.Fatalf("Get payload = %v", got.Payload)
+	}
+}
+
+func testSetUpdate(t *testing.T, storage TaskStorage) {
+	task := newTask("a", "default", domain.TaskPriorityLow, baseTime)
+	task.Version = 42
+	put(t, storage, task)
+	if got := mustGet(t, storage, "a"); got.Version != 1 {
+		t.Fatalf("version of a new task = %d, want 1", got.Version)
+	}
+
+	task.Priority = domain.TaskPriorityCritical
+	put(t, storage, task)
+	got := mustGet(t, storage, "a")
+	if got.Version != 2 || got.Priority != domain.TaskPriorityCritical {
+		t.Fatalf("after overwrite got version %d priority %s", got.Version, got.Priority)
+	}
+}
+
+func testUpdate(t *testing.T, storage TaskStorage) {
+	if _, err := storage.Update("missing", func(task *domain.Task) error { return nil }); !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Fatalf("Update of a missing task: %v, want ErrTaskNotFound", err)
+	}
+
+	put(t, storage, newTask("a", "default", domain.TaskPriorityLow, baseTime))
+	updated, err := storage.Update("a", func(task *domain.Task) error {
+		task.Queue = "other"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Queue != "other" || updated.Version != 2 {
+		t.Fatalf("Update returned queue %s version %d", updated.Queue, updated.Version)
+	}
+	if got := mustGet(t, storage, "a"); got.Queue != "other" || got.Version != 2 {
+		t.Fatalf("after Update got queue %s version %d", got.Queue, got.Version)
+	}
+
+	errRejected := errors.New("rejected")
+	_, err = storage.Update("a", func(task *domain.Task) error {
+		task.Queue = "lost"
+		return errRejected
+	})
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("Update with failing fn: %v, want %v", err, errRejected)
+	}
+	if got := mustGet(t, storage, "a"); got.Queue != "other" || got.Version != 2 {
+		t.Fatalf("failed Update changed the task: queue %s version %d", got.Queue, got.Version)
+	}
+}
+
+func testUpdateStatus(t *testing.T, storage TaskStorage) {
+	if err := storage.UpdateStatus("missing", domain.TaskStatusCompleted); !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Fatalf("UpdateStatus of a missing task: %v, want ErrTaskNotFound", err)
+	}
+
+	put(t, storage, newTask("a", "default", domain.TaskPriorityLow, baseTime))
+	if err := storage.UpdateStatus("a", domain.TaskStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if got := mustGet(t, storage, "a"); got.Status != domain.TaskStatusCancelled || got.Version != 2 {
+		t.Fatalf("after UpdateStatus got status %s version %d", got.Status, got.Version)
+	}
+}
+
+func testCompareAndSwap(t *testing.T, storage TaskStorage) {
+	if _, err := storage.CompareAndSwap("missing", 1, func(task *domain.Task) error { return nil }); !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Fatalf("CompareAndSwap of a missing task: %v, want ErrTaskNotFound", err)
+	}
+
+	put(t, storage, newTask("a", "default", domain.TaskPriorityLow, baseTime))
+	swapped, err := storage.CompareAndSwap("a", 1, func(task *domain.Task) error {
+		task.Type = "sms"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CompareAndSwap with current version: %v", err)
+	}
+	if swapped.Type != "sms" || swapped.Version != 2 {
+		t.Fatalf("CompareAndSwap returned type %s version %d", swapped.Type, swapped.Version)
+	}
+
+	_, err = storage.CompareAndSwap("a", 1, func(task *domain.Task) error {
+		task.Type = "push"
+		return nil
+	})
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("CompareAndSwap with stale version: %v, want ErrVersionConflict", err)
+	}
+	if got := mustGet(t, storage, "a"); got.Type != "sms" || got.Version != 2 {
+		t.Fatalf("stale CompareAndSwap changed the task: type %s version %d", got.Type, got.Version)
+	}
+}
+
+func testDelete(t *testing.T, storage TaskStorage) {
+	if _, err := storage.Delete("missing", func(task *domain.Task) error { return nil }); !errors.Is(err, domain.ErrTaskNotFound) {
+		t.Fatalf("Delete of a missing task: %v, want ErrTaskNotFound", err)
+	}
+
+	put(t, storage, newTask("a", "default", domain.TaskPriorityLow, baseTime))
+	errRejected := errors.New("rejected")
+	if _, err := storage.Delete("a", func(task *domain.Task) error { return errRejected }); !errors.Is(err, errRejected) {
+		t.Fatalf("Delete with failing fn: %v, want %v", err, errRejected)
+	}
+	mustGet(t, storage, "a")
+
+	deleted, err := storage.Delete("a", func(task *domain.Task) error { return nil })
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if deleted.ID != "a" || deleted.Version != 1 {
+		t.Fatalf("Delete returned %s version %d", deleted.ID, deleted.Version)
+	}
+	if _, ok := storage.Get("a"); ok {
+		t.Fatal("deleted task is still returned by Get")
+	}
+	if _, err := storage.ClaimNext(context.Background(), "default", "worker", time.Minute); !errors.Is(err, domain.ErrNoTaskAvailable) {
+		t.Fatalf("ClaimNext after Delete: %v, want ErrNoTaskAvailable", err)
+	}
+}
+
+func testClaimNext(t *testing.T, storage TaskStorage) {
+	ctx := context.Background()
+	done := newTask("done", "default", domain.TaskPriorityCritical, baseTime)
+	done.Status = domain.TaskStatusCompleted
+	put(t, storage,
+		newTask("low", "default", domain.TaskPriorityLow, baseTime),
+		newTask("high-late", "default", domain.TaskPriorityHigh, baseTime.Add(2*time.Second)),
+		newTask("medium", "default", domain.TaskPriorityMedium, baseTime),
+		newTask("high-early", "default", domain.TaskPriorityHigh, baseTime.Add(time.Second)),
+		newTask("other", "other", domain.TaskPriorityCritical, baseTime),
+		done,
+	)
+
+	var claimed []domain.Task
+	for {
+		task, err := storage.ClaimNext(ctx, "default", "worker-1", time.Minute)
+		if errors.Is(err, domain.ErrNoTaskAvailable) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ClaimNext: %v", err)
+		}
+		if task.Status != domain.TaskStatusProcessing || task.WorkerID != "worker-1" || task.LeaseExpiresAt == nil {
+			t.Fatalf("claimed task %s has status %s worker %q lease %v", task.ID, task.Status, task.WorkerID, task.LeaseExpiresAt)
+		}
+		if stored := mustGet(t, storage, task.ID); stored.Status != domain.TaskStatusProcessing || stored.Version != task.Version {
+			t.Fatalf("stored task %s has status %s version %d after claim", task.ID, stored.Status, stored.Version)
+		}
+		claimed = append(claimed, task)
+	}
+	assertIDs(t, "claim order", claimed, "high-early", "high-late", "medium", "low")
+
+	if got := mustGet(t, storage, "other"); got.Status != domain.TaskStatusPending {
+		t.Fatalf("task of another queue has status %s", got.Status)
+	}
+}
+
+func testGetAllFilterStatus(t *testing.T, storage TaskStorage) {
+	ctx := context.Background()
+	failed := newTask("failed", "default", domain.TaskPriorityLow, baseTime)
+	failed.Status = domain.TaskStatusFailed
+	put(t, storage,
+		newTask("low", "default", domain.TaskPriorityLow, baseTime),
+		newTask("high", "other", domain.TaskPriorityHigh, baseTime.Add(time.Second)),
+		failed,
+	)
+
+	pending, err := storage.GetAllFilterStatus(ctx, domain.TaskStatusPending)
+	if err != nil {
+		t.Fatalf("GetAllFilterStatus: %v", err)
+	}
+	assertIDs(t, "pending tasks", pending, "high", "low")
+
+	all, err := storage.GetAllFilterStatus(ctx, "")
+	if err != nil {
+		t.Fatalf("GetAllFilterStatus without status: %v", err)
+	}
+	assertIDs(t, "all tasks", all, "high", "failed", "low")
+
+	if err := storage.UpdateStatus("low", domain.TaskStatusFailed); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	pending, err = storage.GetAllFilterStatus(ctx, domain.TaskStatusPending)
+	if err != nil {
+		t.Fatalf("GetAllFilterStatus: %v", err)
+	}
+	assertIDs(t, "pending tasks after status change", pending, "high")
+}
+
+// listAll собирает все страницы запроса, переходя по курсорам
+func listAll(t *testing.T, storage TaskStorage, query domain.TaskQuery) ([]domain.Task, int) {
+	t.Helper()
+	var result []domain.Task
+	pages := 0
+	for {
+		page, err := storage.ListTasks(context.Background(), query)
+		if err != nil {
+			t.Fatalf("ListTasks: %v", err)
+		}
+		pages++
+		if query.Limit > 0 && len(page.Tasks) > query.Limit {
+			t.Fatalf("page has %d tasks, limit %d", len(page.Tasks), query.Limit)
+		}
+		result = append(result, page.Tasks...)
+		if page.NextCursor == "" {
+			return result, pages
+		}
+		cursor, err := domain.DecodeTaskCursor(page.NextCursor, query.Sort)
+		if err != nil {
+			t.Fatalf("DecodeTaskCursor: %v", err)
+		}
+		query.After = cursor
+	}
+}
+
+func testListTasks(t *testing.T, storage TaskStorage) {
+	var created []string
+	for i, queue := range []string{"a", "b", "a", "a", "b", "a", "a"} {
+		id := string(rune('0'+i)) + "-" + queue
+		task := newTask(id, queue, domain.TaskPriorityMedium, baseTime.Add(time.Duration(i)*time.Second))
+		if i == 3 {
+			task.Priority = domain.TaskPriorityHigh
+			task.WorkerID = "worker-1"
+		}
+		put(t, storage, task)
+		created = append(created, id)
+	}
+	if err := storage.UpdateStatus("5-a", domain.TaskStatusCompleted); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	byCreated := domain.TaskSort{Field: domain.TaskSortCreatedAt}
+	all, pages := listAll(t, storage, domain.TaskQuery{Sort: byCreated, Limit: 3})
+	assertIDs(t, "all tasks by createdAt", all, created...)
+	if pages != 3 {
+		t.Fatalf("7 tasks with limit 3 returned %d pages, want 3", pages)
+	}
+
+	desc, _ := listAll(t, storage, domain.TaskQuery{Sort: domain.TaskSort{Field: domain.TaskSortCreatedAt, Desc: true}, Limit: 2})
+	assertIDs(t, "all tasks by createdAt desc", desc, "6-a", "5-a", "4-b", "3-a", "2-a", "1-b", "0-a")
+
+	queueA, _ := listAll(t, storage, domain.TaskQuery{
+		Filter: domain.TaskFilter{Queue: "a", Status: domain.TaskStatusPending},
+		Sort:   byCreated,
+		Limit:  2,
+	})
+	assertIDs(t, "pending tasks of queue a", queueA, "0-a", "2-a", "3-a", "6-a")
+
+	byPriority, _ := listAll(t, storage, domain.TaskQuery{Filter: domain.TaskFilter{Queue: "b"}, Sort: domain.DefaultTaskSort})
+	assertIDs(t, "tasks of queue b", byPriority, "1-b", "4-b")
+
+	worker, _ := listAll(t, storage, domain.TaskQuery{Filter: domain.TaskFilter{WorkerID: "worker-1"}, Sort: domain.DefaultTaskSort})
+	assertIDs(t, "tasks of worker-1", worker, "3-a")
+
+	created4, _ := listAll(t, storage, domain.TaskQuery{
+		Filter: domain.TaskFilter{CreatedFrom: baseTime.Add(4 * time.Second)},
+		Sort:   byCreated,
+		Limit:  2,
+	})
+	assertIDs(t, "tasks created from +4s", created4, "4-b", "5-a", "6-a")
+
+	predicate, _ := listAll(t, storage, domain.TaskQuery{
+		Filter:    domain.TaskFilter{Queue: "a"},
+		Predicate: func(task *domain.Task) bool { return task.Priority == domain.TaskPriorityHigh },
+		Sort:      byCreated,
+	})
+	assertIDs(t, "high priority tasks of queue a", predicate, "3-a")
+
+	none, _ := listAll(t, storage, domain.TaskQuery{Filter: domain.TaskFilter{Queue: "missing"}, Sort: byCreated, Limit: 2})
+	assertIDs(t, "tasks of a missing queue", none)
+
+	if _, err := storage.Update("2-a", func(task *domain.Task) error {
+		task.Queue = "b"
+		return nil
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	moved, _ := listAll(t, storage, domain.TaskQuery{Filter: domain.TaskFilter{Queue: "b"}, Sort: byCreated})
+	assertIDs(t, "tasks of queue b after move", moved, "1-b", "2-a", "4-b")
+}
+
+func testGetChildren(t *testing.T, storage TaskStorage) {
+	ctx := context.Background()
+	put(t, storage, newTask("parent", "default", domain.TaskPriorityLow, baseTime))
+	for i, id := range []string{"child-2", "child-1", "child-3"} {
+		child := newTask(id, "default", domain.TaskPriorityLow, baseTime.Add(time.Duration(3-i)*time.Second))
+		child.ParentTaskID = "parent"
+		put(t, storage, child)
+	}
+	other := newTask("other-child", "default", domain.TaskPriorityLow, baseTime)
+	other.ParentTaskID = "other"
+	put(t, storage, other)
+
+	children, err := storage.GetChildren(ctx, "parent")
+	if err != nil {
+		t.Fatalf("GetChildren: %v", err)
+	}
+	assertIDs(t, "children by createdAt", children, "child-3", "child-1", "child-2")
+
+	if _, err := storage.Delete("child-1", func(task *domain.Task) error { return nil }); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	children, err = storage.GetChildren(ctx, "parent")
+	if err != nil {
+		t.Fatalf("GetChildren: %v", err)
+	}
+	assertIDs(t, "children after delete", children, "child-3", "child-2")
+
+	none, err := storage.GetChildren(ctx, "child-2")
+	if err != nil {
+		t.Fatalf("GetChildren: %v", err)
+	}
+	assertIDs(t, "children of a leaf", none)
+}
+
+func testGetDependents(t *testing.T, storage TaskStorage) {
+	ctx := context.Background()
+	put(t, storage, newTask("dep", "default", domain.TaskPriorityLow, baseTime))
+	first := newTask("first", "default", domain.TaskPriorityLow, baseTime)
+	first.Status = domain.TaskStatusBlocked
+	first.DependsOn = []string{"dep"}
+	second := newTask("second", "default", domain.TaskPriorityLow, baseTime)
+	second.Status = domain.TaskStatusBlocked
+	second.DependsOn = []string{"other", "dep"}
+	unrelated := newTask("unrelated", "default", domain.TaskPriorityLow, baseTime)
+	unrelated.Status = domain.TaskStatusBlocked
+	unrelated.DependsOn = []string{"other"}
+	put(t, storage, first, second, unrelated)
+
+	dependents, err := storage.GetDependents(ctx, "dep")
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	assertSameIDs(t, "dependents of dep", dependents, "first", "second")
+
+	dependents, err = storage.GetDependents(ctx, "other")
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	assertSameIDs(t, "dependents of other", dependents, "second", "unrelated")
+
+	if _, err := storage.Delete("second", func(task *domain.Task) error { return nil }); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	dependents, err = storage.GetDependents(ctx, "dep")
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	assertSameIDs(t, "dependents after delete", dependents, "first")
+
+	none, err := storage.GetDependents(ctx, "first")
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	assertSameIDs(t, "dependents of a task without dependents", none)
+}
+
+func testReopen(t *testing.T, open OpenTaskStorage) {
+	dir := t.TempDir()
+	storage := open(t, dir)
+
+	child := newTask("child", "default", domain.TaskPriorityLow, baseTime.Add(time.Second))
+	child.ParentTaskID = "parent"
+	child.DependsOn = []string{"parent"}
+	child.Status = domain.TaskStatusBlocked
+	put(t, storage,
+		newTask("parent", "default", domain.TaskPriorityMedium, baseTime),
+		newTask("urgent", "default", domain.TaskPriorityCritical, baseTime.Add(2*time.Second)),
+		newTask("deleted", "default", domain.TaskPriorityCritical, baseTime),
+		child,
+	)
+	if _, err := storage.Update("parent", func(task *domain.Task) error {
+		task.Type = "sms"
+		return nil
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := storage.Delete("deleted", func(task *domain.Task) error { return nil }); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	storage = open(t, dir)
+	t.Cleanup(func() {
+		if err := storage.Close(); err != nil {
+			t.Errorf("close storage: %v", err)
+		}
+	})
+
+	parent := mustGet(t, storage, "parent")
+	if parent.Type != "sms" || parent.Version != 2 || !parent.CreatedAt.Equal(baseTime) || parent.Payload["to"] != "parent" {
+		t.Fatalf("reopened parent: %+v", parent)
+	}
+	if _, ok := storage.Get("deleted"); ok {
+		t.Fatal("deleted task is restored after reopen")
+	}
+	if got := mustGet(t, storage, "child"); got.Status != domain.TaskStatusBlocked || got.Version != 1 {
+		t.Fatalf("reopened child has status %s version %d", got.Status, got.Version)
+	}
+
+	ctx := context.Background()
+	page, err := storage.ListTasks(ctx, domain.TaskQuery{Filter: domain.TaskFilter{Queue: "default", Status: domain.TaskStatusPending}, Sort: domain.DefaultTaskSort})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	assertIDs(t, "pending tasks after reopen", page.Tasks, "urgent", "parent")
+
+	children, err := storage.GetChildren(ctx, "parent")
+	if err != nil {
+		t.Fatalf("GetChildren: %v", err)
+	}
+	assertIDs(t, "children after reopen", children, "child")
+	dependents, err := storage.GetDependents(ctx, "parent")
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	assertIDs(t, "dependents after reopen", dependents, "child")
+
+	var claimed []domain.Task
+	for {
+		task, err := storage.ClaimNext(ctx, "default", "worker-1", time.Minute)
+		if errors.Is(err, domain.ErrNoTaskAvailable) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ClaimNext: %v", err)
+		}
+		claimed = append(claimed, task)
+	}
+	assertIDs(t, "claim order after reopen", claimed, "urgent", "parent")
+
+	if _, err := storage.CompareAndSwap("parent", 3, func(task *domain.Task) error { return nil }); err != nil {
+		t.Fatalf("CompareAndSwap after reopen: %v", err)
+	}
+}
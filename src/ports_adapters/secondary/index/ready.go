@@ -0,0 +1,150 @@
+package index
+
+import (
+	"container/heap"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+const priorityLevels = 4
+
+type readyItem struct {
+	key       string
+	queue     string
+	weight    int
+	createdAt time.Time
+	index     int
+}
+
+type readyHeap []*readyItem
+
+func (h readyHeap) Len() int { return len(h) }
+func (h readyHeap) Less(i, j int) bool {
+	if !h[i].createdAt.Equal(h[j].createdAt) {
+		return h[i].createdAt.Before(h[j].createdAt)
+	}
+	return h[i].key < h[j].key
+}
+func (h readyHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *readyHeap) Push(x any) {
+	item := x.(*readyItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *readyHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// ReadyIndex хранит готовые к выдаче задачи каждой очереди по уровням приоритета,
+// внутри уровня — в порядке создания
+type ReadyIndex struct {
+	mu     sync.Mutex
+	aging  time.Duration
+	queues map[string]*[priorityLevels]readyHeap
+	items  map[string]*readyItem
+}
+
+func NewReadyIndex(aging time.Duration) *ReadyIndex {
+	return &ReadyIndex{
+		aging:  aging,
+		queues: make(map[string]*[priorityLevels]readyHeap),
+		items:  make(map[string]*readyItem),
+	}
+}
+
+func (r *ReadyIndex) Add(task domain.Task) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	weight := task.Priority.Weight()
+	if item, ok := r.items[task.ID]; ok {
+		if item.queue == task.Queue && item.weight == weight {
+			return
+		}
+		r.removeLocked(item)
+	}
+
+	levels, ok := r.queues[task.Queue]
+	if !ok {
+		levels = &[priorityLevels]readyHeap{}
+		r.queues[task.Queue] = levels
+	}
+	item := &readyItem{
+		key:       task.ID,
+		queue:     task.Queue,
+		weight:    weight,
+		createdAt: task.CreatedAt,
+	}
+	heap.Push(&levels[weight], item)
+	r.items[task.ID] = item
+}
+
+func (r *ReadyIndex) Remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[key]; ok {
+		r.removeLocked(item)
+	}
+}
+
+func (r *ReadyIndex) removeLocked(item *readyItem) {
+	levels := r.queues[item.queue]
+	heap.Remove(&levels[item.weight], item.index)
+	delete(r.items, item.key)
+
+	for _, level := range levels {
+		if level.Len() > 0 {
+			return
+		}
+	}
+	delete(r.queues, item.queue)
+}
+
+// Peek возвращает задачу очереди, которую следует выдать следующей: с наибольшим
+// приоритетом с учетом старения, при равенстве — самую раннюю
+func (r *ReadyIndex) Peek(queue string, now time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels, ok := r.queues[queue]
+	if !ok {
+		return "", false
+	}
+
+	var (
+		best      *readyItem
+		bestScore int
+	)
+	for weight := priorityLevels - 1; weight >= 0; weight-- {
+		if levels[weight].Len() == 0 {
+			continue
+		}
+		head := levels[weight][0]
+		score := r.score(head, now)
+		if best == nil || score > bestScore || (score == bestScore && head.createdAt.Before(best.createdAt)) {
+			best, bestScore = head, score
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.key, true
+}
+
+// score повышает приоритет задачи на один уровень за каждый интервал aging ожидания
+func (r *ReadyIndex) score(item *readyItem, now time.Time) int {
+	if r.aging <= 0 {
+		return item.weight
+	}
+	return item.weight + int(now.Sub(item.createdAt)/r.aging)
+}
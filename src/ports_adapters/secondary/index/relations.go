@@ -0,0 +1,83 @@
+package index
+
+import (
+	"svc-task_master/src/domain"
+	"sync"
+)
+
+// Relations хранит связи задач: подзадачи каждого родителя и задачи, зависящие
+// от каждой задачи, чтобы их поиск не требовал просмотра всего хранилища
+type Relations struct {
+	mu         sync.RWMutex
+	children   map[string]map[string]struct{}
+	dependents map[string]map[string]struct{}
+}
+
+func NewRelations() *Relations {
+	return &Relations{
+		children:   make(map[string]map[string]struct{}),
+		dependents: make(map[string]map[string]struct{}),
+	}
+}
+
+// Track поддерживает связи в актуальном состоянии; вызывается хранилищем
+// под блокировкой задачи после каждого ее изменения (next == nil при удалении)
+func (r *Relations) Track(key string, prev, next *domain.Task) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev != nil {
+		unlink(r.children, prev.ParentTaskID, key)
+		for _, dep := range prev.DependsOn {
+			unlink(r.dependents, dep, key)
+		}
+	}
+	if next != nil {
+		link(r.children, next.ParentTaskID, key)
+		for _, dep := range next.DependsOn {
+			link(r.dependents, dep, key)
+		}
+	}
+}
+
+// Children возвращает ключи подзадач задачи
+func (r *Relations) Children(key string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return members(r.children[key])
+}
+
+// Dependents возвращает ключи задач, зависящих от задачи
+func (r *Relations) Dependents(key string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return members(r.dependents[key])
+}
+
+func link(sets map[string]map[string]struct{}, owner string, key string) {
+	if owner == "" {
+		return
+	}
+	set, ok := sets[owner]
+	if !ok {
+		set = make(map[string]struct{})
+		sets[owner] = set
+	}
+	set[key] = struct{}{}
+}
+
+func unlink(sets map[string]map[string]struct{}, owner string, key string) {
+	set := sets[owner]
+	delete(set, key)
+	if len(set) == 0 {
+		delete(sets, owner)
+	}
+}
+
+func members(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,145 @@
+package index
+
+import (
+	"container/heap"
+	"errors"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+const schedulerIdleWait = time.Minute
+
+var (
+	errNotScheduled = errors.New("task is not scheduled")
+	// errUnchanged прерывает изменение задачи, которую не нужно перезаписывать
+	errUnchanged = errors.New("task is not changed")
+)
+
+// UpdateFunc атомарно изменяет задачу в хранилище (см. domain.IInMemoRepository.Update)
+type UpdateFunc func(key string, fn func(task *domain.Task) error) (domain.Task, error)
+
+type scheduledItem struct {
+	key string
+	at  time.Time
+}
+
+type scheduleHeap []scheduledItem
+
+func (h scheduleHeap) Len() int           { return len(h) }
+func (h scheduleHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h scheduleHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scheduleHeap) Push(x any)        { *h = append(*h, x.(scheduledItem)) }
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// Scheduler хранит отложенные задачи в min-heap по времени запуска
+type Scheduler struct {
+	mu    sync.Mutex
+	items scheduleHeap
+	wake  chan struct{}
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{wake: make(chan struct{}, 1)}
+}
+
+func (q *Scheduler) Push(key string, at time.Time) {
+	q.mu.Lock()
+	heap.Push(&q.items, scheduledItem{key: key, at: at})
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Scheduler) popDue(now time.Time) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var keys []string
+	for q.items.Len() > 0 && !q.items[0].at.After(now) {
+		keys = append(keys, heap.Pop(&q.items).(scheduledItem).key)
+	}
+	return keys
+}
+
+func (q *Scheduler) next() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.items.Len() == 0 {
+		return time.Time{}, false
+	}
+	return q.items[0].at, true
+}
+
+// Run по наступлении ScheduledAt делает отложенные и ожидающие
+// повтора задачи доступными для выдачи воркерам, пока не закрыт done.
+// ready добавляет задачу в индекс готовых задач
+func (q *Scheduler) Run(logger domain.ILogger, update UpdateFunc, ready func(task domain.Task), done <-chan struct{}) {
+	timer := time.NewTimer(schedulerIdleWait)
+	defer timer.Stop()
+
+	for {
+		now := time.Now()
+		promoted := 0
+		for _, key := range q.popDue(now) {
+			if q.promote(update, ready, key, now) {
+				promoted++
+			}
+		}
+		if promoted > 0 {
+			logger.Debug("Promoted scheduled tasks",
+				slog.Attr{Key: "promoted_count", Value: slog.IntValue(promoted)},
+			)
+		}
+
+		wait := schedulerIdleWait
+		if at, ok := q.next(); ok {
+			wait = time.Until(at)
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// promote переводит наступившую отложенную задачу в pending, а задачу,
+// ожидающую повтора, возвращает в индекс готовых задач. Задача в retrying
+// при этом не меняется, поэтому и не перезаписывается: запись подняла бы ее версию,
+// и клиент, прочитавший задачу до наступления повтора, получил бы 412
+func (q *Scheduler) promote(update UpdateFunc, ready func(task domain.Task), key string, now time.Time) bool {
+	var notDueUntil time.Time
+	_, err := update(key, func(task *domain.Task) error {
+		if task.Status != domain.TaskStatusScheduled && task.Status != domain.TaskStatusRetrying {
+			return errNotScheduled
+		}
+		if !task.IsDue(now) {
+			notDueUntil = *task.ScheduledAt
+			return errNotScheduled
+		}
+		if task.Status == domain.TaskStatusScheduled {
+			return task.TransitionTo(domain.TaskStatusPending, now)
+		}
+		// Индекс готовых задач обновляется под блокировкой задачи, как и при записи
+		ready(*task)
+		return errUnchanged
+	})
+	if !notDueUntil.IsZero() {
+		q.Push(key, notDueUntil)
+	}
+	return err == nil || errors.Is(err, errUnchanged)
+}
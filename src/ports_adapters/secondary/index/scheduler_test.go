@@ -0,0 +1,48 @@
+package index
+
+import (
+	"svc-task_master/src/domain"
+	"testing"
+	"time"
+)
+
+func TestPromoteDoesNotRewriteRetryingTask(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Second)
+	tasks := map[string]domain.Task{
+		"scheduled": {ID: "scheduled", Status: domain.TaskStatusScheduled, ScheduledAt: &due, Version: 1},
+		"retrying":  {ID: "retrying", Status: domain.TaskStatusRetrying, ScheduledAt: &due, Version: 1},
+	}
+	writes := map[string]int{}
+	update := func(key string, fn func(task *domain.Task) error) (domain.Task, error) {
+		task := tasks[key]
+		if err := fn(&task); err != nil {
+			return domain.Task{}, err
+		}
+		task.Version++
+		tasks[key] = task
+		writes[key]++
+		return task, nil
+	}
+	var ready []string
+	addReady := func(task domain.Task) {
+		ready = append(ready, task.ID)
+	}
+
+	scheduler := NewScheduler()
+	for _, key := range []string{"scheduled", "retrying"} {
+		if !scheduler.promote(update, addReady, key, now) {
+			t.Fatalf("promote(%s) = false, want true", key)
+		}
+	}
+
+	if got := tasks["scheduled"]; got.Status != domain.TaskStatusPending || writes["scheduled"] != 1 {
+		t.Fatalf("scheduled task: status %s after %d writes, want pending after 1 write", got.Status, writes["scheduled"])
+	}
+	if got := tasks["retrying"]; got.Version != 1 || writes["retrying"] != 0 {
+		t.Fatalf("retrying task: version %d after %d writes, want version 1 and no writes", got.Version, writes["retrying"])
+	}
+	if len(ready) != 1 || ready[0] != "retrying" {
+		t.Fatalf("tasks added to the ready index = %v, want [retrying]", ready)
+	}
+}
@@ -0,0 +1,79 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+var errClaimConflict = errors.New("task was changed before claim")
+
+// Readiness объединяет индекс готовых задач и очередь отложенных задач
+// и используется адаптерами хранилища задач
+type Readiness struct {
+	ready     *ReadyIndex
+	scheduler *Scheduler
+}
+
+func NewReadiness(aging time.Duration) *Readiness {
+	return &Readiness{
+		ready:     NewReadyIndex(aging),
+		scheduler: NewScheduler(),
+	}
+}
+
+// Track поддерживает индексы в актуальном состоянии; вызывается хранилищем
+// под блокировкой задачи после каждого ее изменения (next == nil при удалении)
+func (r *Readiness) Track(key string, prev, next *domain.Task) {
+	if next == nil {
+		r.ready.Remove(key)
+		return
+	}
+
+	now := time.Now()
+	if next.IsClaimable(now) {
+		r.ready.Add(*next)
+		return
+	}
+	r.ready.Remove(key)
+
+	waiting := next.Status == domain.TaskStatusScheduled || next.Status == domain.TaskStatusRetrying
+	statusChanged := prev == nil || prev.Status != next.Status
+	if waiting && statusChanged && next.ScheduledAt != nil {
+		r.scheduler.Push(key, *next.ScheduledAt)
+	}
+}
+
+func (r *Readiness) RunScheduler(logger domain.ILogger, update UpdateFunc, done <-chan struct{}) {
+	r.scheduler.Run(logger, update, r.ready.Add, done)
+}
+
+// Claim выбирает из индекса следующую задачу очереди и захватывает ее через update
+func (r *Readiness) Claim(ctx context.Context, update UpdateFunc, queue string, workerID string, lease time.Duration) (domain.Task, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return domain.Task{}, err
+		}
+
+		now := time.Now()
+		key, ok := r.ready.Peek(queue, now)
+		if !ok {
+			return domain.Task{}, domain.ErrNoTaskAvailable
+		}
+
+		// Между выбором из индекса и захватом задачу мог изменить другой запрос,
+		// поэтому условия проверяются повторно под блокировкой задачи
+		task, err := update(key, func(task *domain.Task) error {
+			if task.Queue != queue || !task.IsClaimable(now) {
+				return errClaimConflict
+			}
+			return task.Claim(workerID, lease, now)
+		})
+		if errors.Is(err, errClaimConflict) || errors.Is(err, domain.ErrTaskNotFound) {
+			r.ready.Remove(key)
+			continue
+		}
+		return task, err
+	}
+}
@@ -1,17 +1,58 @@
 package db
 
 import (
+	"log/slog"
+	"svc-task_master/src/common/config"
 	"svc-task_master/src/domain"
 	"svc-task_master/src/ports_adapters/secondary/inmemory/db/task_repo"
-	"time"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/kv"
+	"svc-task_master/src/ports_adapters/secondary/repository"
 )
 
-type Repository struct {
-	InMemoryDB domain.IInMemoRepository
-}
+func NewRepository(logger domain.ILogger, appConfig *config.Config) (*repository.Repository, error) {
+	keeper, taskArchive, err := repository.NewRetention(logger, appConfig)
+	if err != nil {
+		return nil, err
+	}
+	cfg := appConfig.MemoryDB
+	taskStorage := task_repo.NewSharderStorage(cfg.NumShards, keeper, appConfig.Retention.CheckInterval, cfg.PriorityAging, logger)
+
+	if cfg.WALDir != "" {
+		wal, err := task_repo.OpenWAL(cfg.WALDir, task_repo.FsyncPolicy(cfg.WALFsync), cfg.WALFsyncInterval, logger)
+		if err != nil {
+			taskStorage.Close()
+			return nil, err
+		}
+		snapshots := task_repo.NewSnapshotStore(cfg.WALDir, cfg.SnapshotRetention, logger)
+		tasks, records, err := taskStorage.Restore(snapshots, wal)
+		if err != nil {
+			wal.Close()
+			taskStorage.Close()
+			return nil, err
+		}
+		logger.Info("Restored task storage",
+			slog.Attr{Key: "dir", Value: slog.StringValue(cfg.WALDir)},
+			slog.Attr{Key: "snapshotTasks", Value: slog.IntValue(tasks)},
+			slog.Attr{Key: "walRecords", Value: slog.IntValue(records)},
+		)
+		if cfg.SnapshotInterval > 0 {
+			logger.Info("Starting snapshot goroutine", slog.Attr{Key: "interval", Value: slog.StringValue(cfg.SnapshotInterval.String())})
+			go taskStorage.RunSnapshots(cfg.SnapshotInterval)
+		}
+	}
+	taskStorage.Start()
 
-func NewRepository(logger domain.ILogger, sharedNum int, ttl time.Duration) *Repository {
-	return &Repository{
-		InMemoryDB: task_repo.NewSharderStorage(sharedNum, ttl, logger),
+	repo, err := repository.Compose(logger, appConfig, taskStorage, taskArchive, repository.StateStore{
+		Dir: cfg.WALDir,
+		Options: kv.Options{
+			Sync:         kv.SyncPolicy(cfg.WALFsync),
+			SyncInterval: cfg.WALFsyncInterval,
+		},
+		MergeInterval: cfg.SnapshotInterval,
+	})
+	if err != nil {
+		taskStorage.Close()
+		return nil, err
 	}
+	return repo, nil
 }
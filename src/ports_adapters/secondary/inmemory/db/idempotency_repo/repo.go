@@ -0,0 +1,191 @@
+package idempotency_repo
+
+import (
+	"encoding/json"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	TaskID    string    `json:"taskId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Store хранилище ключ-значение, в котором сохраняются ключи идемпотентности
+type Store interface {
+	Put(key string, value []byte) error
+	Delete(key string) error
+	ForEach(fn func(key string, value []byte) error) error
+}
+
+// IdempotencyStorage хранит ключи идемпотентности создания задач в течение TTL
+type IdempotencyStorage struct {
+	logger domain.ILogger
+	store  Store
+	ttl    time.Duration
+	mu     sync.Mutex
+	keys   map[string]entry
+	done   chan struct{}
+}
+
+var _ domain.IIdempotencyRepository = &IdempotencyStorage{}
+
+func NewIdempotencyStorage(ttl time.Duration, logger domain.ILogger) *IdempotencyStorage {
+	storage := newIdempotencyStorage(ttl, logger)
+	storage.start()
+	return storage
+}
+
+// OpenIdempotencyStorage загружает из store ключи с неистекшим сроком и сохраняет в него
+// все последующие изменения, чтобы повторы запросов распознавались и после перезапуска
+func OpenIdempotencyStorage(store Store, ttl time.Duration, logger domain.ILogger) (*IdempotencyStorage, error) {
+	storage := newIdempotencyStorage(ttl, logger)
+	storage.store = store
+
+	now := time.Now()
+	var expired []string
+	err := store.ForEach(func(key string, value []byte) error {
+		var current entry
+		if err := json.Unmarshal(value, &current); err != nil {
+			return err
+		}
+		if !now.Before(current.ExpiresAt) {
+			expired = append(expired, key)
+			return nil
+		}
+		storage.keys[key] = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range expired {
+		if err := store.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info("Restored idempotency keys", slog.Attr{Key: "count", Value: slog.IntValue(len(storage.keys))})
+
+	storage.start()
+	return storage, nil
+}
+
+func newIdempotencyStorage(ttl time.Duration, logger domain.ILogger) *IdempotencyStorage {
+	return &IdempotencyStorage{
+		logger: logger,
+		ttl:    ttl,
+		keys:   make(map[string]entry),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *IdempotencyStorage) start() {
+	s.logger.Info("Starting idempotency keys cleanup goroutine", slog.Attr{Key: "ttl", Value: slog.StringValue(s.ttl.String())})
+	go s.ClearExpired(cleanupInterval(s.ttl))
+}
+
+// cleanupInterval истекшие ключи не влияют на Reserve, поэтому их достаточно
+// удалять раз в минуту или чаще, если TTL короче
+func cleanupInterval(ttl time.Duration) time.Duration {
+	switch {
+	case ttl < time.Second:
+		return time.Second
+	case ttl < time.Minute:
+		return ttl
+	default:
+		return time.Minute
+	}
+}
+
+func (s *IdempotencyStorage) Reserve(key string, taskID string, now time.Time) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.keys[key]; ok && now.Before(current.ExpiresAt) {
+		s.logger.Debug("Idempotency key replayed",
+			slog.Attr{Key: "key", Value: slog.StringValue(key)},
+			slog.Attr{Key: "task_id", Value: slog.StringValue(current.TaskID)},
+		)
+		return current.TaskID, true, nil
+	}
+	reserved := entry{TaskID: taskID, ExpiresAt: now.Add(s.ttl)}
+	if err := s.persist(key, reserved); err != nil {
+		return "", false, err
+	}
+	s.keys[key] = reserved
+	return taskID, false, nil
+}
+
+func (s *IdempotencyStorage) Release(key string, taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.keys[key]; ok && current.TaskID == taskID {
+		delete(s.keys, key)
+		s.remove(key)
+	}
+}
+
+func (s *IdempotencyStorage) persist(key string, reserved entry) error {
+	if s.store == nil {
+		return nil
+	}
+	value, err := json.Marshal(reserved)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(key, value)
+}
+
+// remove удаляет ключ из store. Ошибка только журналируется: ключ, оставшийся
+// в store, после перезапуска будет отброшен при загрузке, когда истечет его срок
+func (s *IdempotencyStorage) remove(key string) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.Delete(key); err != nil {
+		s.logger.Error("Failed to delete idempotency key",
+			slog.Attr{Key: "key", Value: slog.StringValue(key)},
+			slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+		)
+	}
+}
+
+func (s *IdempotencyStorage) ClearExpired(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		s.mu.Lock()
+		removed := 0
+		for key, current := range s.keys {
+			if !now.Before(current.ExpiresAt) {
+				delete(s.keys, key)
+				s.remove(key)
+				removed++
+			}
+		}
+		s.mu.Unlock()
+
+		if removed > 0 {
+			s.logger.Debug("Removed expired idempotency keys",
+				slog.Attr{Key: "count", Value: slog.IntValue(removed)},
+			)
+		}
+	}
+}
+
+// Close останавливает очистку истекших ключей
+func (s *IdempotencyStorage) Close() error {
+	close(s.done)
+	return nil
+}
@@ -0,0 +1,143 @@
+package schedule_repo
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"svc-task_master/src/domain"
+	"sync"
+)
+
+// Store хранилище ключ-значение, в котором сохраняются расписания
+type Store interface {
+	Put(key string, value []byte) error
+	Delete(key string) error
+	ForEach(fn func(key string, value []byte) error) error
+}
+
+type ScheduleStorage struct {
+	logger domain.ILogger
+	store  Store
+	mu     sync.RWMutex
+	Data   map[string]*domain.Schedule
+}
+
+var _ domain.IScheduleRepository = &ScheduleStorage{}
+
+func NewScheduleStorage(logger domain.ILogger) *ScheduleStorage {
+	return &ScheduleStorage{
+		logger: logger,
+		Data:   make(map[string]*domain.Schedule),
+	}
+}
+
+// OpenScheduleStorage загружает расписания из store и сохраняет в него все последующие изменения
+func OpenScheduleStorage(store Store, logger domain.ILogger) (*ScheduleStorage, error) {
+	s := NewScheduleStorage(logger)
+	s.store = store
+	err := store.ForEach(func(key string, value []byte) error {
+		var schedule domain.Schedule
+		if err := json.Unmarshal(value, &schedule); err != nil {
+			return err
+		}
+		s.Data[key] = &schedule
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Restored schedules", slog.Attr{Key: "count", Value: slog.IntValue(len(s.Data))})
+	return s, nil
+}
+
+// persist сохраняет расписание в store до изменения данных в памяти
+func (s *ScheduleStorage) persist(schedule *domain.Schedule) error {
+	if s.store == nil {
+		return nil
+	}
+	value, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(schedule.ID, value)
+}
+
+func (s *ScheduleStorage) Get(id string) (domain.Schedule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if schedule, ok := s.Data[id]; ok {
+		return *schedule, true
+	}
+	return domain.Schedule{}, false
+}
+
+func (s *ScheduleStorage) Create(schedule domain.Schedule) error {
+	s.logger.Debug("Creating schedule",
+		slog.Attr{Key: "id", Value: slog.StringValue(schedule.ID)},
+		slog.Attr{Key: "cron", Value: slog.StringValue(schedule.Cron)},
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.persist(&schedule); err != nil {
+		return err
+	}
+	s.Data[schedule.ID] = &schedule
+	return nil
+}
+
+func (s *ScheduleStorage) Update(id string, fn func(schedule *domain.Schedule) error) (domain.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.Data[id]
+	if !ok {
+		return domain.Schedule{}, domain.ErrScheduleNotFound
+	}
+	updated := *current
+	if err := fn(&updated); err != nil {
+		return domain.Schedule{}, err
+	}
+	if err := s.persist(&updated); err != nil {
+		return domain.Schedule{}, err
+	}
+	s.Data[id] = &updated
+	return updated, nil
+}
+
+func (s *ScheduleStorage) Delete(id string) error {
+	s.logger.Debug("Deleting schedule",
+		slog.Attr{Key: "id", Value: slog.StringValue(id)},
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.Data[id]; !ok {
+		return domain.ErrScheduleNotFound
+	}
+	if s.store != nil {
+		if err := s.store.Delete(id); err != nil {
+			return err
+		}
+	}
+	delete(s.Data, id)
+	return nil
+}
+
+func (s *ScheduleStorage) GetAll(ctx context.Context) ([]domain.Schedule, error) {
+	s.mu.RLock()
+	result := make([]domain.Schedule, 0, len(s.Data))
+	for _, schedule := range s.Data {
+		result = append(result, *schedule)
+	}
+	s.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
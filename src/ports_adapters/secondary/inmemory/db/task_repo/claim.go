@@ -0,0 +1,32 @@
+package task_repo
+
+import (
+	"context"
+	"log/slog"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+func (s *SharderStorage) ClaimNext(ctx context.Context, queue string, workerID string, lease time.Duration) (domain.Task, error) {
+	s.logger.Debug("Claiming next task",
+		slog.Attr{Key: "queue", Value: slog.StringValue(queue)},
+		slog.Attr{Key: "worker_id", Value: slog.StringValue(workerID)},
+	)
+
+	task, err := s.readiness.Claim(ctx, s.Update, queue, workerID, lease)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	s.logger.Debug("Task claimed",
+		slog.Attr{Key: "key", Value: slog.StringValue(task.ID)},
+		slog.Attr{Key: "worker_id", Value: slog.StringValue(workerID)},
+	)
+	return task, nil
+}
+
+// PromoteScheduled по наступлении ScheduledAt делает отложенные и ожидающие
+// повтора задачи доступными для выдачи воркерам
+func (s *SharderStorage) PromoteScheduled() {
+	s.readiness.RunScheduler(s.logger, s.Update, s.done)
+}
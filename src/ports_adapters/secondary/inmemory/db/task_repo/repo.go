@@ -4,75 +4,209 @@ import (
 	"context"
 	"hash/fnv"
 	"log/slog"
+	"sort"
 	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/index"
+	"svc-task_master/src/ports_adapters/secondary/retention"
 	"sync"
 	"time"
 )
 
 type SharderStorage struct {
-	logger domain.ILogger
-	Shard  []*Sharder
+	*retention.Keeper
+	logger            domain.ILogger
+	Shard             []*Sharder
+	retentionInterval time.Duration
+	readiness         *index.Readiness
+	wal               *WAL
+	snapshots         *SnapshotStore
+	snapshotMu        sync.Mutex
+	snapshotSeq       uint64
+	done              chan struct{}
 }
 
 type Sharder struct {
-	mu   sync.RWMutex
-	Data map[string]*domain.Task
+	mu    sync.RWMutex
+	Data  map[string]*domain.Task
+	index *secondaryIndex
 }
 
 var _ domain.IInMemoRepository = &SharderStorage{}
+var _ domain.IRetentionRepository = &SharderStorage{}
 
-func NewSharderStorage(numSharders int, ttl time.Duration, logger domain.ILogger) *SharderStorage {
+func NewSharderStorage(numSharders int, keeper *retention.Keeper, retentionInterval time.Duration, priorityAging time.Duration, logger domain.ILogger) *SharderStorage {
 	sharders := make([]*Sharder, numSharders)
 	for i := 0; i < numSharders; i++ {
-		sharders[i] = &Sharder{Data: make(map[string]*domain.Task)}
+		sharders[i] = &Sharder{Data: make(map[string]*domain.Task), index: newSecondaryIndex()}
 	}
-	sharderStorage := &SharderStorage{
-		logger: logger,
-		Shard:  sharders,
+	return &SharderStorage{
+		Keeper:            keeper,
+		logger:            logger,
+		Shard:             sharders,
+		retentionInterval: retentionInterval,
+		readiness:         index.NewReadiness(priorityAging),
+		done:              make(chan struct{}),
 	}
-	if ttl > 0 {
-		logger.Info("Starting TTL cleanup goroutine", slog.Attr{Key: "ttl", Value: slog.StringValue(ttl.String())})
-		go sharderStorage.ClearForTTL(ttl)
+}
+
+// Start запускает очистку по правилам хранения и перевод отложенных задач в готовые.
+// Вызывается после Restore, чтобы изменения фоновых задач попадали в журнал
+func (s *SharderStorage) Start() {
+	if s.retentionInterval > 0 {
+		s.logger.Info("Starting TTL cleanup goroutine", slog.Attr{Key: "interval", Value: slog.StringValue(s.retentionInterval.String())})
+		go s.ClearForTTL(s.retentionInterval)
 	}
-	return sharderStorage
+	s.logger.Info("Starting scheduled tasks promotion goroutine")
+	go s.PromoteScheduled()
 }
 
-func (s *SharderStorage) ClearForTTL(ttl time.Duration) {
-	wg := sync.WaitGroup{}
-	ticker := time.NewTicker(5 * time.Second)
+// ClearForTTL удаляет задачи, время хранения которых по правилам хранения истекло.
+// Задачи сначала передаются в архив и удаляются, только если не изменились с момента отбора
+func (s *SharderStorage) ClearForTTL(interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		now := time.Now()
-		cutoff := now.Add(-ttl)
-		deletedCount := 0
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
 
+		now := time.Now()
+		var expired []domain.Task
 		for _, shard := range s.Shard {
-			wg.Add(1)
-			go func(sh *Sharder) {
-				sh.mu.Lock()
-				defer sh.mu.Unlock()
-				defer wg.Done()
+			shard.mu.RLock()
+			for _, task := range shard.Data {
+				if s.Expired(task, now) {
+					expired = append(expired, *task)
+				}
+			}
+			shard.mu.RUnlock()
+		}
+		if len(expired) > 0 {
+			if err := s.Archive(expired, now); err != nil {
+				s.logger.Error("Failed to archive expired tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+				continue
+			}
+		}
 
-				for key, task := range sh.Data {
-					if task.UpdatedAt.Before(cutoff) {
-						delete(sh.Data, key)
-						deletedCount++
-					}
+		deletedCount := 0
+		for _, task := range expired {
+			shard := s.getSharder(task.ID)
+			shard.mu.Lock()
+			if current, ok := shard.Data[task.ID]; ok && current.UpdatedAt.Equal(task.UpdatedAt) && current.Status == task.Status {
+				if err := s.onWrite(shard, task.ID, current, nil); err != nil {
+					shard.mu.Unlock()
+					continue
 				}
-			}(shard)
+				delete(shard.Data, task.ID)
+				s.Evicted(current)
+				deletedCount++
+			}
+			shard.mu.Unlock()
 		}
-		wg.Wait()
+		s.Checked(now)
 
 		if deletedCount > 0 {
 			s.logger.Debug("Cleaned up expired tasks",
 				slog.Attr{Key: "deleted_count", Value: slog.IntValue(deletedCount)},
-				slog.Attr{Key: "cutoff_time", Value: slog.StringValue(cutoff.String())},
 			)
 		}
 	}
 }
 
+// onWrite дописывает изменение задачи в журнал и обновляет индексы хранилища;
+// вызывается под блокировкой шарда перед каждым изменением задачи (next == nil
+// при удалении). Если запись в журнал не удалась, изменение не применяется
+func (s *SharderStorage) onWrite(shard *Sharder, key string, prev, next *domain.Task) error {
+	if err := s.appendWAL(key, next); err != nil {
+		return err
+	}
+	s.track(shard, key, prev, next)
+	return nil
+}
+
+// track поддерживает индексы хранилища и шарда в актуальном состоянии
+func (s *SharderStorage) track(shard *Sharder, key string, prev, next *domain.Task) {
+	s.readiness.Track(key, prev, next)
+	shard.index.update(key, prev, next)
+}
+
+func (s *SharderStorage) appendWAL(key string, next *domain.Task) error {
+	if s.wal == nil {
+		return nil
+	}
+	record := walRecord{Op: walOpPut, Key: key, Task: next}
+	if next == nil {
+		record.Op = walOpDelete
+	}
+	if err := s.wal.append(record); err != nil {
+		s.logger.Error("Failed to append WAL record",
+			slog.Attr{Key: "key", Value: slog.StringValue(key)},
+			slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+		)
+		return err
+	}
+	return nil
+}
+
+// Restore загружает последний снимок, воспроизводит поверх него хвост журнала
+// и начинает записывать в журнал все изменения. Возвращает количество
+// задач из снимка и количество воспроизведенных записей журнала
+func (s *SharderStorage) Restore(snapshots *SnapshotStore, wal *WAL) (int, int, error) {
+	header, records, ok, err := snapshots.loadLatest()
+	if err != nil {
+		return 0, 0, err
+	}
+	fromSeq := uint64(0)
+	if ok {
+		for _, record := range records {
+			s.applyRecord(record)
+		}
+		fromSeq = header.WALSeq
+		s.snapshotSeq = header.WALSeq
+	}
+
+	count, err := wal.replay(fromSeq, s.applyRecord)
+	if err != nil {
+		return len(records), count, err
+	}
+	s.wal = wal
+	s.snapshots = snapshots
+	return len(records), count, nil
+}
+
+func (s *SharderStorage) applyRecord(record walRecord) {
+	shard := s.getSharder(record.Key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	prev := shard.Data[record.Key]
+	if record.Op == walOpDelete || record.Task == nil {
+		if prev != nil {
+			delete(shard.Data, record.Key)
+			s.track(shard, record.Key, prev, nil)
+		}
+		return
+	}
+	task := *record.Task
+	shard.Data[record.Key] = &task
+	s.track(shard, record.Key, prev, &task)
+}
+
+func (s *SharderStorage) Close() error {
+	close(s.done)
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if s.wal == nil {
+		return nil
+	}
+	return s.wal.Close()
+}
+
 func (s *SharderStorage) getSharder(key string) *Sharder {
 	hashKey := fnv.New64a()
 	hashKey.Write([]byte(key))
@@ -85,6 +219,25 @@ func (s *SharderStorage) GetAllFilterStatus(ctx context.Context, status domain.T
 		slog.Attr{Key: "status", Value: slog.StringValue(string(status))},
 	)
 
+	if status != "" {
+		var result []domain.Task
+		_, err := s.visitIndexed(ctx, domain.TaskFilter{Status: status}, func(task *domain.Task) {
+			if task.Status == status {
+				result = append(result, *task)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].ClaimPrecedes(result[j])
+		})
+		s.logger.Debug("Successfully retrieved filtered tasks",
+			slog.Attr{Key: "count", Value: slog.IntValue(len(result))},
+		)
+		return result, nil
+	}
+
 	var result []domain.Task
 	resultChan := make(chan []domain.Task, len(s.Shard))
 
@@ -124,6 +277,9 @@ func (s *SharderStorage) GetAllFilterStatus(ctx context.Context, status domain.T
 			return nil, ctx.Err()
 		case filtered, ok := <-resultChan:
 			if !ok {
+				sort.Slice(result, func(i, j int) bool {
+					return result[i].ClaimPrecedes(result[j])
+				})
 				s.logger.Debug("Successfully retrieved filtered tasks",
 					slog.Attr{Key: "count", Value: slog.IntValue(len(result))},
 				)
@@ -134,6 +290,75 @@ func (s *SharderStorage) GetAllFilterStatus(ctx context.Context, status domain.T
 	}
 }
 
+// visitIndexed вызывает fn под блокировкой чтения шарда для задач из самого узкого
+// индекса шарда среди полей, заданных в фильтре; fn должна сама проверять остальные
+// условия выборки. false означает, что ни одно индексируемое поле в фильтре не задано
+func (s *SharderStorage) visitIndexed(ctx context.Context, filter domain.TaskFilter, fn func(task *domain.Task)) (bool, error) {
+	conditions := indexConditions(filter)
+	if len(conditions) == 0 {
+		return false, nil
+	}
+	return true, s.visitKeys(ctx, func(index *secondaryIndex) map[string]struct{} {
+		return index.narrowest(conditions)
+	}, fn)
+}
+
+// visitKeys вызывает fn для задач, ключи которых keys выбирает из индекса шарда,
+// беря блокировки шардов по одной
+func (s *SharderStorage) visitKeys(ctx context.Context, keys func(index *secondaryIndex) map[string]struct{}, fn func(task *domain.Task)) error {
+	for _, shard := range s.Shard {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		shard.mu.RLock()
+		for key := range keys(shard.index) {
+			if task, ok := shard.Data[key]; ok {
+				fn(task)
+			}
+		}
+		shard.mu.RUnlock()
+	}
+	return nil
+}
+
+// visitAll вызывает fn для каждой задачи, беря блокировки шардов по одной
+func (s *SharderStorage) visitAll(ctx context.Context, fn func(task *domain.Task)) error {
+	for _, shard := range s.Shard {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		shard.mu.RLock()
+		for _, task := range shard.Data {
+			fn(task)
+		}
+		shard.mu.RUnlock()
+	}
+	return nil
+}
+
+func (s *SharderStorage) ListTasks(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error) {
+	s.logger.Debug("Listing tasks",
+		slog.Attr{Key: "sort", Value: slog.StringValue(query.Sort.String())},
+		slog.Attr{Key: "limit", Value: slog.IntValue(query.Limit)},
+	)
+
+	pager := domain.NewTaskPager(query)
+	add := func(task *domain.Task) {
+		if query.Match(task) {
+			pager.Add(*task)
+		}
+	}
+
+	indexed, err := s.visitIndexed(ctx, query.Filter, add)
+	if err == nil && !indexed {
+		err = s.visitAll(ctx, add)
+	}
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+	return pager.Page(), nil
+}
+
 func (s *SharderStorage) Get(key string) (domain.Task, bool) {
 	s.logger.Debug("Getting task by key",
 		slog.Attr{Key: "key", Value: slog.StringValue(key)},
@@ -156,7 +381,7 @@ func (s *SharderStorage) Get(key string) (domain.Task, bool) {
 	return domain.Task{}, false
 }
 
-func (s *SharderStorage) SetUpdate(key string, data domain.Task) {
+func (s *SharderStorage) SetUpdate(key string, data domain.Task) error {
 	s.logger.Debug("Setting/updating task",
 		slog.Attr{Key: "key", Value: slog.StringValue(key)},
 		slog.Attr{Key: "status", Value: slog.StringValue(string(data.Status))},
@@ -164,19 +389,124 @@ func (s *SharderStorage) SetUpdate(key string, data domain.Task) {
 
 	shard := s.getSharder(key)
 	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	prev := shard.Data[key]
+	data.AdvanceVersion(prev)
+	if err := s.onWrite(shard, key, prev, &data); err != nil {
+		return err
+	}
 	shard.Data[key] = &data
-	shard.mu.Unlock()
+	return nil
 }
 
-func (s *SharderStorage) UpdateStatus(key string, status domain.TaskStatus) {
+func (s *SharderStorage) UpdateStatus(key string, status domain.TaskStatus) error {
 	s.logger.Debug("Updating task status",
 		slog.Attr{Key: "key", Value: slog.StringValue(key)},
 		slog.Attr{Key: "new_status", Value: slog.StringValue(string(status))},
 	)
 
+	_, err := s.Update(key, func(task *domain.Task) error {
+		task.Status = status
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+	return err
+}
+
+func (s *SharderStorage) Update(key string, fn func(task *domain.Task) error) (domain.Task, error) {
+	s.logger.Debug("Updating task",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	shard := s.getSharder(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	current, ok := shard.Data[key]
+	if !ok {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+
+	updated := *current
+	if err := fn(&updated); err != nil {
+		return domain.Task{}, err
+	}
+	updated.AdvanceVersion(current)
+	if err := s.onWrite(shard, key, current, &updated); err != nil {
+		return domain.Task{}, err
+	}
+	shard.Data[key] = &updated
+	return updated, nil
+}
+
+func (s *SharderStorage) Delete(key string, fn func(task *domain.Task) error) (domain.Task, error) {
+	s.logger.Debug("Deleting task",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
 	shard := s.getSharder(key)
 	shard.mu.Lock()
-	shard.Data[key].Status = status
-	shard.Data[key].UpdatedAt = time.Now()
-	shard.mu.Unlock()
+	defer shard.mu.Unlock()
+
+	current, ok := shard.Data[key]
+	if !ok {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+
+	deleted := *current
+	if err := fn(&deleted); err != nil {
+		return domain.Task{}, err
+	}
+	if err := s.onWrite(shard, key, current, nil); err != nil {
+		return domain.Task{}, err
+	}
+	delete(shard.Data, key)
+	return *current, nil
+}
+
+func (s *SharderStorage) CompareAndSwap(key string, expected int64, fn func(task *domain.Task) error) (domain.Task, error) {
+	return s.Update(key, func(task *domain.Task) error {
+		if err := task.CheckVersion(expected); err != nil {
+			return err
+		}
+		return fn(task)
+	})
+}
+
+func (s *SharderStorage) GetDependents(ctx context.Context, key string) ([]domain.Task, error) {
+	s.logger.Debug("Getting dependent tasks",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	var result []domain.Task
+	err := s.visitKeys(ctx, func(index *secondaryIndex) map[string]struct{} {
+		return index.dependents[key]
+	}, func(task *domain.Task) {
+		result = append(result, *task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SharderStorage) GetChildren(ctx context.Context, key string) ([]domain.Task, error) {
+	s.logger.Debug("Getting child tasks",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	var result []domain.Task
+	err := s.visitKeys(ctx, func(index *secondaryIndex) map[string]struct{} {
+		return index.values[indexParent][key]
+	}, func(task *domain.Task) {
+		result = append(result, *task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
 }
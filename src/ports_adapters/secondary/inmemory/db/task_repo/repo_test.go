@@ -0,0 +1,50 @@
+package task_repo
+
+import (
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/archive"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"testing"
+)
+
+func openSharderStorage(t *testing.T, dir string) *SharderStorage {
+	t.Helper()
+	keeper := retention.NewKeeper(domain.RetentionPolicy{}, 0, archive.Nop{})
+	storage := NewSharderStorage(4, keeper, 0, 0, contract.NopLogger{})
+	wal, err := OpenWAL(dir, FsyncAlways, 0, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("OpenWAL: %v", err)
+	}
+	if _, _, err := storage.Restore(NewSnapshotStore(dir, 2, contract.NopLogger{}), wal); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	storage.Start()
+	return storage
+}
+
+// snapshottingStorage снимает снимок перед закрытием, чтобы при повторном
+// открытии хранилище восстанавливалось из снимка, а не только из журнала
+type snapshottingStorage struct {
+	*SharderStorage
+}
+
+func (s snapshottingStorage) Close() error {
+	if err := s.Snapshot(); err != nil {
+		return err
+	}
+	return s.SharderStorage.Close()
+}
+
+func TestSharderStorageContract(t *testing.T) {
+	t.Run("wal", func(t *testing.T) {
+		contract.RunTaskRepository(t, func(t *testing.T, dir string) contract.TaskStorage {
+			return openSharderStorage(t, dir)
+		})
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		contract.RunTaskRepository(t, func(t *testing.T, dir string) contract.TaskStorage {
+			return snapshottingStorage{openSharderStorage(t, dir)}
+		})
+	})
+}
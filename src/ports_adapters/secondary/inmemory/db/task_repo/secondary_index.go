@@ -0,0 +1,143 @@
+package task_repo
+
+import (
+	"slices"
+	"svc-task_master/src/domain"
+)
+
+type indexField string
+
+const (
+	indexStatus   indexField = "status"
+	indexQueue    indexField = "queue"
+	indexType     indexField = "type"
+	indexPriority indexField = "priority"
+	indexWorker   indexField = "workerId"
+	indexParent   indexField = "parentTaskId"
+)
+
+var indexedFields = map[indexField]func(task *domain.Task) string{
+	indexStatus:   func(task *domain.Task) string { return string(task.Status) },
+	indexQueue:    func(task *domain.Task) string { return task.Queue },
+	indexType:     func(task *domain.Task) string { return task.Type },
+	indexPriority: func(task *domain.Task) string { return string(task.Priority) },
+	indexWorker:   func(task *domain.Task) string { return task.WorkerID },
+	indexParent:   func(task *domain.Task) string { return task.ParentTaskID },
+}
+
+// secondaryIndex хранит для каждого индексируемого поля множества ключей задач
+// шарда по значениям поля, чтобы выборки с фильтром не просматривали все задачи.
+// Индекс принадлежит шарду и защищен его блокировкой, поэтому запись в разные
+// шарды не конкурирует за общий индекс.
+// Пустые значения не индексируются: по ним не бывает выборок, а у необязательных
+// полей вроде workerId множество пустого значения содержало бы почти все задачи.
+// Зависимости задачи многозначны, поэтому индексируются отдельно от полей
+type secondaryIndex struct {
+	values map[indexField]map[string]map[string]struct{}
+	// dependents ключи задач шарда по ID задач, от которых они зависят
+	dependents map[string]map[string]struct{}
+}
+
+func newSecondaryIndex() *secondaryIndex {
+	values := make(map[indexField]map[string]map[string]struct{}, len(indexedFields))
+	for field := range indexedFields {
+		values[field] = make(map[string]map[string]struct{})
+	}
+	return &secondaryIndex{
+		values:     values,
+		dependents: make(map[string]map[string]struct{}),
+	}
+}
+
+// update вызывается под блокировкой записи шарда при каждом изменении задачи (next == nil при удалении)
+func (i *secondaryIndex) update(key string, prev, next *domain.Task) {
+	for field, value := range indexedFields {
+		if prev != nil && next != nil && value(prev) == value(next) {
+			continue
+		}
+		if prev != nil {
+			i.remove(field, value(prev), key)
+		}
+		if next != nil {
+			i.add(field, value(next), key)
+		}
+	}
+
+	if prev != nil && next != nil && slices.Equal(prev.DependsOn, next.DependsOn) {
+		return
+	}
+	if prev != nil {
+		for _, dep := range prev.DependsOn {
+			removeKey(i.dependents, dep, key)
+		}
+	}
+	if next != nil {
+		for _, dep := range next.DependsOn {
+			addKey(i.dependents, dep, key)
+		}
+	}
+}
+
+func (i *secondaryIndex) add(field indexField, value string, key string) {
+	if value == "" {
+		return
+	}
+	addKey(i.values[field], value, key)
+}
+
+func (i *secondaryIndex) remove(field indexField, value string, key string) {
+	if value == "" {
+		return
+	}
+	removeKey(i.values[field], value, key)
+}
+
+func addKey(sets map[string]map[string]struct{}, value, key string) {
+	keys, ok := sets[value]
+	if !ok {
+		keys = make(map[string]struct{})
+		sets[value] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+func removeKey(sets map[string]map[string]struct{}, value, key string) {
+	keys := sets[value]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(sets, value)
+	}
+}
+
+// indexConditions возвращает значения индексируемых полей, заданные в фильтре
+func indexConditions(filter domain.TaskFilter) map[indexField]string {
+	conditions := make(map[indexField]string)
+	for field, value := range map[indexField]string{
+		indexStatus:   string(filter.Status),
+		indexQueue:    filter.Queue,
+		indexType:     filter.Type,
+		indexPriority: string(filter.Priority),
+		indexWorker:   filter.WorkerID,
+		indexParent:   filter.ParentTaskID,
+	} {
+		if value != "" {
+			conditions[field] = value
+		}
+	}
+	return conditions
+}
+
+// narrowest возвращает ключи задач шарда из самого узкого индекса среди условий.
+// Вызывается под блокировкой чтения шарда; остальные условия фильтра проверяет вызывающий код
+func (i *secondaryIndex) narrowest(conditions map[indexField]string) map[string]struct{} {
+	var narrowest map[string]struct{}
+	found := false
+	for field, value := range conditions {
+		set := i.values[field][value]
+		if !found || len(set) < len(narrowest) {
+			narrowest = set
+			found = true
+		}
+	}
+	return narrowest
+}
@@ -0,0 +1,164 @@
+package task_repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/archive"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	benchTasks  = 1_000_000
+	benchShards = 100
+	benchQueues = 100
+)
+
+var (
+	benchOnce    sync.Once
+	benchStorage *SharderStorage
+)
+
+// benchmarkStorage заполняет хранилище миллионом задач: 1% в failed,
+// 9% в pending, остальные completed, поровну по benchQueues очередям
+func benchmarkStorage(b *testing.B) *SharderStorage {
+	b.Helper()
+	benchOnce.Do(func() {
+		keeper := retention.NewKeeper(domain.RetentionPolicy{}, 0, archive.Nop{})
+		storage := NewSharderStorage(benchShards, keeper, 0, 0, contract.NopLogger{})
+		createdAt := time.Now().Add(-time.Hour)
+		for i := 0; i < benchTasks; i++ {
+			status := domain.TaskStatusCompleted
+			switch {
+			case i%100 == 0:
+				status = domain.TaskStatusFailed
+			case i%10 == 0:
+				status = domain.TaskStatusPending
+			}
+			id := "task-" + strconv.Itoa(i)
+			task := domain.Task{
+				ID:        id,
+				Type:      "email",
+				Queue:     fmt.Sprintf("queue-%02d", i%benchQueues),
+				Status:    status,
+				Priority:  domain.TaskPriorityMedium,
+				CreatedAt: createdAt.Add(time.Duration(i) * time.Microsecond),
+				UpdatedAt: createdAt,
+			}
+			if err := storage.SetUpdate(id, task); err != nil {
+				b.Fatal(err)
+			}
+		}
+		benchStorage = storage
+	})
+	return benchStorage
+}
+
+// scanFilterStatus выбирает задачи со статусом полным просмотром шардов
+func scanFilterStatus(ctx context.Context, s *SharderStorage, status domain.TaskStatus) ([]domain.Task, error) {
+	var result []domain.Task
+	err := s.visitAll(ctx, func(task *domain.Task) {
+		if task.Status == status {
+			result = append(result, *task)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClaimPrecedes(result[j])
+	})
+	return result, nil
+}
+
+// scanListTasks выполняет запрос страницы полным просмотром шардов
+func scanListTasks(ctx context.Context, s *SharderStorage, query domain.TaskQuery) (domain.TaskPage, error) {
+	pager := domain.NewTaskPager(query)
+	err := s.visitAll(ctx, func(task *domain.Task) {
+		if query.Match(task) {
+			pager.Add(*task)
+		}
+	})
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+	return pager.Page(), nil
+}
+
+func BenchmarkGetAllFilterStatus(b *testing.B) {
+	storage := benchmarkStorage(b)
+	ctx := context.Background()
+
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := storage.GetAllFilterStatus(ctx, domain.TaskStatusFailed); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := scanFilterStatus(ctx, storage, domain.TaskStatusFailed); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkListTasks(b *testing.B) {
+	storage := benchmarkStorage(b)
+	ctx := context.Background()
+	query := domain.TaskQuery{
+		Filter: domain.TaskFilter{Queue: "queue-07", Status: domain.TaskStatusPending},
+		Sort:   domain.DefaultTaskSort,
+		Limit:  50,
+	}
+
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := storage.ListTasks(ctx, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := scanListTasks(ctx, storage, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkSetUpdateParallel измеряет конкурентную запись: индексы шардов
+// не должны сводить запись в разные шарды к одной блокировке
+func BenchmarkSetUpdateParallel(b *testing.B) {
+	keeper := retention.NewKeeper(domain.RetentionPolicy{}, 0, archive.Nop{})
+	storage := NewSharderStorage(benchShards, keeper, 0, 0, contract.NopLogger{})
+	defer storage.Close()
+
+	var next atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := next.Add(1)
+			id := "task-" + strconv.FormatInt(n, 10)
+			task := domain.Task{
+				ID:       id,
+				Type:     "email",
+				Queue:    fmt.Sprintf("queue-%02d", n%benchQueues),
+				Status:   domain.TaskStatusPending,
+				Priority: domain.TaskPriorityMedium,
+			}
+			if err := storage.SetUpdate(id, task); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
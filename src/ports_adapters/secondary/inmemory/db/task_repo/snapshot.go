@@ -0,0 +1,258 @@
+package task_repo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"svc-task_master/src/domain"
+	"time"
+)
+
+const snapshotPattern = "snapshot-%016d.snap"
+
+// snapshotHeader первая запись файла снимка. WALSeq — номер сегмента журнала,
+// начатого перед снятием снимка: при загрузке поверх снимка воспроизводятся
+// сегменты начиная с него
+type snapshotHeader struct {
+	WALSeq    uint64    `json:"walSeq"`
+	CreatedAt time.Time `json:"createdAt"`
+	Tasks     int       `json:"tasks"`
+}
+
+// SnapshotStore хранит снимки хранилища в каталоге журнала
+type SnapshotStore struct {
+	logger    domain.ILogger
+	dir       string
+	retention int
+}
+
+func NewSnapshotStore(dir string, retention int, logger domain.ILogger) *SnapshotStore {
+	if retention < 1 {
+		retention = 1
+	}
+	return &SnapshotStore{
+		logger:    logger,
+		dir:       dir,
+		retention: retention,
+	}
+}
+
+func (s *SnapshotStore) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf(snapshotPattern, seq))
+}
+
+// write сохраняет снимок через временный файл, чтобы на диске не оставались недописанные снимки
+func (s *SnapshotStore) write(header snapshotHeader, payloads [][]byte) error {
+	path := s.path(header.WALSeq)
+	tmpPath := path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	writer := bufio.NewWriter(file)
+	if err := writeJSONFrame(writer, header); err != nil {
+		file.Close()
+		return err
+	}
+	for _, payload := range payloads {
+		if err := writeFrame(writer, payload); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// loadLatest читает самый свежий целый снимок. Поврежденные снимки пропускаются
+// с ошибкой в логе. Если снимков нет, возвращается ok == false
+func (s *SnapshotStore) loadLatest() (snapshotHeader, []walRecord, bool, error) {
+	seqs, err := listSequenced(s.dir, snapshotPattern)
+	if err != nil {
+		return snapshotHeader{}, nil, false, err
+	}
+	for i := len(seqs) - 1; i >= 0; i-- {
+		header, records, err := s.read(s.path(seqs[i]))
+		if err != nil {
+			s.logger.Error("Snapshot is corrupted, skipping",
+				slog.Attr{Key: "snapshot", Value: slog.StringValue(s.path(seqs[i]))},
+				slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+			)
+			continue
+		}
+		return header, records, true, nil
+	}
+	return snapshotHeader{}, nil, false, nil
+}
+
+func (s *SnapshotStore) read(path string) (snapshotHeader, []walRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return snapshotHeader{}, nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var header snapshotHeader
+	if err := readJSONFrame(reader, &header); err != nil {
+		return snapshotHeader{}, nil, err
+	}
+
+	records := make([]walRecord, 0, header.Tasks)
+	for {
+		var record walRecord
+		err := readJSONFrame(reader, &record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return snapshotHeader{}, nil, err
+		}
+		records = append(records, record)
+	}
+	if len(records) != header.Tasks {
+		return snapshotHeader{}, nil, fmt.Errorf("%w: expected %d tasks, got %d", errWALCorrupted, header.Tasks, len(records))
+	}
+	return header, records, nil
+}
+
+// prune удаляет снимки сверх retention и возвращает номер сегмента журнала,
+// необходимого самому старому из оставшихся снимков
+func (s *SnapshotStore) prune() (uint64, error) {
+	seqs, err := listSequenced(s.dir, snapshotPattern)
+	if err != nil || len(seqs) == 0 {
+		return 0, err
+	}
+	keepFrom := len(seqs) - s.retention
+	if keepFrom < 0 {
+		keepFrom = 0
+	}
+	for _, seq := range seqs[:keepFrom] {
+		if err := os.Remove(s.path(seq)); err != nil {
+			return 0, err
+		}
+	}
+	return seqs[keepFrom], nil
+}
+
+func writeJSONFrame(writer io.Writer, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFrame(writer, payload)
+}
+
+func readJSONFrame(reader io.Reader, v interface{}) error {
+	payload, _, err := readFrame(reader)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %v", errWALCorrupted, err)
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Snapshot сохраняет снимок всех шардов. Перед снятием снимка журнал переключается
+// на новый сегмент, после чего шарды копируются по одному под блокировкой чтения,
+// не останавливая запись в остальные шарды. Изменения, сделанные во время снятия
+// снимка, попадают в новый сегмент и при загрузке применяются поверх снимка,
+// поэтому результат соответствует состоянию на момент окончания записи журнала
+func (s *SharderStorage) Snapshot() error {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if s.wal == nil || s.snapshots == nil || s.wal.unchangedSince(s.snapshotSeq) {
+		return nil
+	}
+
+	started := time.Now()
+	seq, err := s.wal.rotate()
+	if err != nil {
+		return err
+	}
+
+	var payloads [][]byte
+	for _, shard := range s.Shard {
+		shard.mu.RLock()
+		for key, task := range shard.Data {
+			payload, err := json.Marshal(walRecord{Op: walOpPut, Key: key, Task: task})
+			if err != nil {
+				shard.mu.RUnlock()
+				return err
+			}
+			payloads = append(payloads, payload)
+		}
+		shard.mu.RUnlock()
+	}
+
+	header := snapshotHeader{WALSeq: seq, CreatedAt: started, Tasks: len(payloads)}
+	if err := s.snapshots.write(header, payloads); err != nil {
+		return err
+	}
+
+	s.snapshotSeq = seq
+
+	oldestSeq, err := s.snapshots.prune()
+	if err != nil {
+		return err
+	}
+	removed, err := s.wal.removeBefore(oldestSeq)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Snapshot saved",
+		slog.Attr{Key: "walSeq", Value: slog.Uint64Value(seq)},
+		slog.Attr{Key: "tasks", Value: slog.IntValue(len(payloads))},
+		slog.Attr{Key: "removedSegments", Value: slog.IntValue(removed)},
+		slog.Attr{Key: "duration", Value: slog.StringValue(time.Since(started).String())},
+	)
+	return nil
+}
+
+func (s *SharderStorage) RunSnapshots(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.Snapshot(); err != nil {
+				s.logger.Error("Failed to save snapshot", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package task_repo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+type FsyncPolicy string
+
+const (
+	FsyncAlways   FsyncPolicy = "always"
+	FsyncInterval FsyncPolicy = "interval"
+	FsyncNever    FsyncPolicy = "never"
+)
+
+const (
+	walSegmentPattern = "wal-%016d.log"
+	frameHeaderSize   = 8
+	frameMaxLen       = 64 << 20
+)
+
+type walOp string
+
+const (
+	walOpPut    walOp = "put"
+	walOpDelete walOp = "delete"
+)
+
+var (
+	errWALCorrupted = errors.New("wal record corrupted")
+	errWALFailed    = errors.New("wal failed")
+)
+
+type walRecord struct {
+	Op   walOp        `json:"op"`
+	Key  string       `json:"key"`
+	Task *domain.Task `json:"task,omitempty"`
+}
+
+// WAL журнал упреждающей записи изменений хранилища, разбитый на сегменты.
+// Каждая запись имеет вид [длина uint32][crc32 uint32][JSON walRecord]
+type WAL struct {
+	mu       sync.Mutex
+	logger   domain.ILogger
+	dir      string
+	file     *os.File
+	seq      uint64
+	size     int64
+	policy   FsyncPolicy
+	interval time.Duration
+	dirty    bool
+	// failed ошибка, после которой хвост сегмента не удалось вернуть к последней
+	// целой записи; дальнейшая запись в журнал отклоняется
+	failed error
+	done   chan struct{}
+}
+
+func OpenWAL(dir string, policy FsyncPolicy, interval time.Duration, logger domain.ILogger) (*WAL, error) {
+	switch policy {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+	default:
+		return nil, fmt.Errorf("invalid wal fsync policy: %s, must be one of: always, interval, never", policy)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	segments, err := listSequenced(dir, walSegmentPattern)
+	if err != nil {
+		return nil, err
+	}
+	seq := uint64(1)
+	if len(segments) > 0 {
+		seq = segments[len(segments)-1]
+	}
+
+	w := &WAL{
+		logger:   logger,
+		dir:      dir,
+		policy:   policy,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	if err := w.openSegment(seq); err != nil {
+		return nil, err
+	}
+	if policy == FsyncInterval && interval > 0 {
+		go w.syncLoop()
+	}
+	return w, nil
+}
+
+func (w *WAL) segmentPath(seq uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf(walSegmentPattern, seq))
+}
+
+func (w *WAL) openSegment(seq uint64) error {
+	file, err := os.OpenFile(w.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.seq = seq
+	w.size = info.Size()
+	return nil
+}
+
+// replay читает сегменты, начиная с fromSeq, и передает записи в apply.
+// Поврежденный хвост последнего сегмента (например, после аварийной остановки
+// во время записи) отбрасывается, повреждение в предыдущих сегментах считается ошибкой
+func (w *WAL) replay(fromSeq uint64, apply func(walRecord)) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	segments, err := listSequenced(w.dir, walSegmentPattern)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i, seq := range segments {
+		if seq < fromSeq {
+			continue
+		}
+		n, err := w.replaySegment(seq, i == len(segments)-1, apply)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func (w *WAL) replaySegment(seq uint64, last bool, apply func(walRecord)) (int, error) {
+	path := w.segmentPath(seq)
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var (
+		offset int64
+		count  int
+	)
+	for {
+		payload, size, err := readFrame(reader)
+		if err == io.EOF {
+			return count, nil
+		}
+		var record walRecord
+		if err == nil {
+			if err = json.Unmarshal(payload, &record); err != nil {
+				err = fmt.Errorf("%w: %v", errWALCorrupted, err)
+			}
+		}
+		if err != nil {
+			if !last {
+				return count, fmt.Errorf("segment %s at offset %d: %w", path, offset, err)
+			}
+			w.logger.Error("WAL is corrupted, truncating tail",
+				slog.Attr{Key: "segment", Value: slog.StringValue(path)},
+				slog.Attr{Key: "offset", Value: slog.Int64Value(offset)},
+				slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+			)
+			return count, os.Truncate(path, offset)
+		}
+		apply(record)
+		offset += size
+		count++
+	}
+}
+
+// append дописывает запись в журнал. Если запись или fsync не удались, сегмент
+// обрезается до последней целой записи: вызывающий не применяет изменение, поэтому
+// оно не должно воспроизводиться при восстановлении, а оборванный кадр не должен
+// отбрасывать при восстановлении записи, сделанные после него
+func (w *WAL) append(record walRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed != nil {
+		return w.failed
+	}
+	err = writeFrame(w.file, payload)
+	if err == nil && w.policy == FsyncAlways {
+		err = w.file.Sync()
+	}
+	if err != nil {
+		if truncErr := w.file.Truncate(w.size); truncErr != nil {
+			w.failed = fmt.Errorf("%w: %v", errWALFailed, errors.Join(err, truncErr))
+			return w.failed
+		}
+		return err
+	}
+	w.size += int64(frameHeaderSize + len(payload))
+	if w.policy != FsyncAlways {
+		w.dirty = true
+	}
+	return nil
+}
+
+// rotate закрывает текущий сегмент и начинает новый. Возвращает номер нового сегмента:
+// все записи, сделанные после вызова, попадут в него или в следующие сегменты
+func (w *WAL) rotate() (uint64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.file.Sync(); err != nil {
+		return 0, err
+	}
+	w.dirty = false
+	if err := w.file.Close(); err != nil {
+		return 0, err
+	}
+	if err := w.openSegment(w.seq + 1); err != nil {
+		return 0, err
+	}
+	return w.seq, nil
+}
+
+// unchangedSince сообщает, что после переключения на сегмент seq в журнал ничего не записано
+func (w *WAL) unchangedSince(seq uint64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.seq == seq && w.size == 0
+}
+
+// removeBefore удаляет сегменты с номером меньше seq
+func (w *WAL) removeBefore(seq uint64) (int, error) {
+	segments, err := listSequenced(w.dir, walSegmentPattern)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, s := range segments {
+		if s >= seq {
+			break
+		}
+		if err := os.Remove(w.segmentPath(s)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (w *WAL) syncLoop() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if err := w.sync(); err != nil {
+				w.logger.Error("Failed to fsync WAL", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}
+	}
+}
+
+func (w *WAL) sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.dirty {
+		return nil
+	}
+	w.dirty = false
+	return w.file.Sync()
+}
+
+func (w *WAL) Close() error {
+	close(w.done)
+	if err := w.sync(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
+
+func readFrame(reader io.Reader) ([]byte, int64, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, fmt.Errorf("%w: %v", errWALCorrupted, err)
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	if length > frameMaxLen {
+		return nil, 0, fmt.Errorf("%w: invalid record length %d", errWALCorrupted, length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errWALCorrupted, err)
+	}
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return nil, 0, fmt.Errorf("%w: checksum mismatch", errWALCorrupted)
+	}
+	return payload, int64(frameHeaderSize + len(payload)), nil
+}
+
+func writeFrame(writer io.Writer, payload []byte) error {
+	buf := make([]byte, frameHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[frameHeaderSize:], payload)
+	_, err := writer.Write(buf)
+	return err
+}
+
+// listSequenced возвращает отсортированные номера файлов каталога, подходящих под шаблон
+func listSequenced(dir, pattern string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		var seq uint64
+		if _, err := fmt.Sscanf(entry.Name(), pattern, &seq); err != nil {
+			continue
+		}
+		if entry.Name() != fmt.Sprintf(pattern, seq) {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
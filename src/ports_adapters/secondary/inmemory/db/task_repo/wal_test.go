@@ -0,0 +1,53 @@
+package task_repo
+
+import (
+	"errors"
+	"os"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"testing"
+)
+
+func TestWALAppendFailureKeepsSegmentConsistent(t *testing.T) {
+	dir := t.TempDir()
+	wal, err := OpenWAL(dir, FsyncAlways, 0, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("OpenWAL: %v", err)
+	}
+	if err := wal.append(walRecord{Op: walOpPut, Key: "a", Task: &domain.Task{ID: "a"}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	// Запись в дескриптор только для чтения не удается, и обрезать через него
+	// сегмент тоже нельзя: журнал должен перестать принимать записи
+	file := wal.file
+	readOnly, err := os.Open(wal.segmentPath(wal.seq))
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	wal.file = readOnly
+	if err := wal.append(walRecord{Op: walOpPut, Key: "b", Task: &domain.Task{ID: "b"}}); !errors.Is(err, errWALFailed) {
+		t.Fatalf("append to a read-only segment: %v, want errWALFailed", err)
+	}
+	if err := wal.append(walRecord{Op: walOpDelete, Key: "a"}); !errors.Is(err, errWALFailed) {
+		t.Fatalf("append after failure: %v, want errWALFailed", err)
+	}
+	wal.file = file
+	readOnly.Close()
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	wal, err = OpenWAL(dir, FsyncAlways, 0, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("OpenWAL: %v", err)
+	}
+	defer wal.Close()
+	var keys []string
+	if _, err := wal.replay(0, func(record walRecord) { keys = append(keys, record.Key) }); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("replayed keys %v, want [a]", keys)
+	}
+}
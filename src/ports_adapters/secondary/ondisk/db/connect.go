@@ -0,0 +1,48 @@
+package db
+
+import (
+	"log/slog"
+	"svc-task_master/src/common/config"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/db/task_repo"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/kv"
+	"svc-task_master/src/ports_adapters/secondary/repository"
+)
+
+func NewRepository(logger domain.ILogger, cfg *config.Config) (*repository.Repository, error) {
+	opts := kv.Options{
+		Sync:         kv.SyncPolicy(cfg.DiskDB.Fsync),
+		SyncInterval: cfg.DiskDB.FsyncInterval,
+		MaxFileSize:  cfg.DiskDB.MaxFileSize,
+	}
+	store, err := kv.Open(cfg.DiskDB.Dir, opts, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	keeper, taskArchive, err := repository.NewRetention(logger, cfg)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	taskStorage, err := task_repo.NewDiskStorage(store, cfg.MemoryDB.NumShards, keeper, cfg.Retention.CheckInterval, cfg.MemoryDB.PriorityAging, logger)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	if cfg.DiskDB.MergeInterval > 0 {
+		logger.Info("Starting KV merge goroutine", slog.Attr{Key: "interval", Value: slog.StringValue(cfg.DiskDB.MergeInterval.String())})
+		go taskStorage.RunMerge(cfg.DiskDB.MergeInterval)
+	}
+
+	repo, err := repository.Compose(logger, cfg, taskStorage, taskArchive, repository.StateStore{
+		Dir:           cfg.DiskDB.Dir,
+		Options:       opts,
+		MergeInterval: cfg.DiskDB.MergeInterval,
+	})
+	if err != nil {
+		taskStorage.Close()
+		return nil, err
+	}
+	return repo, nil
+}
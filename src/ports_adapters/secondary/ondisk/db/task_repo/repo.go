@@ -0,0 +1,420 @@
+package task_repo
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/index"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/kv"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"sync"
+	"time"
+)
+
+// DiskStorage хранилище задач поверх встроенного on-disk KV. В памяти хранятся
+// только индекс ключей KV, индексы готовых и отложенных задач и связи задач
+type DiskStorage struct {
+	*retention.Keeper
+	logger    domain.ILogger
+	store     *kv.Store
+	locks     []sync.Mutex
+	readiness *index.Readiness
+	relations *index.Relations
+	done      chan struct{}
+}
+
+var _ domain.IInMemoRepository = &DiskStorage{}
+var _ domain.IRetentionRepository = &DiskStorage{}
+
+func NewDiskStorage(store *kv.Store, numLocks int, keeper *retention.Keeper, retentionInterval time.Duration, priorityAging time.Duration, logger domain.ILogger) (*DiskStorage, error) {
+	if numLocks < 1 {
+		numLocks = 1
+	}
+	diskStorage := &DiskStorage{
+		Keeper:    keeper,
+		logger:    logger,
+		store:     store,
+		locks:     make([]sync.Mutex, numLocks),
+		readiness: index.NewReadiness(priorityAging),
+		relations: index.NewRelations(),
+		done:      make(chan struct{}),
+	}
+
+	count := 0
+	err := store.ForEach(func(key string, value []byte) error {
+		var task domain.Task
+		if err := json.Unmarshal(value, &task); err != nil {
+			return err
+		}
+		diskStorage.track(key, nil, &task)
+		count++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Loaded task indexes from disk", slog.Attr{Key: "tasks", Value: slog.IntValue(count)})
+
+	if retentionInterval > 0 {
+		logger.Info("Starting TTL cleanup goroutine", slog.Attr{Key: "interval", Value: slog.StringValue(retentionInterval.String())})
+		go diskStorage.ClearForTTL(retentionInterval)
+	}
+	logger.Info("Starting scheduled tasks promotion goroutine")
+	go diskStorage.readiness.RunScheduler(logger, diskStorage.Update, diskStorage.done)
+	return diskStorage, nil
+}
+
+func (s *DiskStorage) lock(key string) *sync.Mutex {
+	hashKey := fnv.New64a()
+	hashKey.Write([]byte(key))
+	return &s.locks[hashKey.Sum64()%uint64(len(s.locks))]
+}
+
+func (s *DiskStorage) read(key string) (*domain.Task, error) {
+	value, ok, err := s.store.Get(key)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var task domain.Task
+	if err := json.Unmarshal(value, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// write назначает задаче следующую версию, сохраняет ее и обновляет индексы;
+// вызывается под блокировкой ключа
+func (s *DiskStorage) write(key string, prev *domain.Task, next *domain.Task) error {
+	next.AdvanceVersion(prev)
+	value, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(key, value); err != nil {
+		return err
+	}
+	s.track(key, prev, next)
+	return nil
+}
+
+// track поддерживает индексы хранилища в актуальном состоянии; вызывается под блокировкой ключа
+func (s *DiskStorage) track(key string, prev, next *domain.Task) {
+	s.readiness.Track(key, prev, next)
+	s.relations.Track(key, prev, next)
+}
+
+func (s *DiskStorage) logError(msg string, key string, err error) {
+	s.logger.Error(msg,
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+		slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+	)
+}
+
+// ClearForTTL удаляет задачи, время хранения которых по правилам хранения истекло.
+// Задачи сначала передаются в архив и удаляются, только если не изменились с момента отбора
+func (s *DiskStorage) ClearForTTL(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		expired, err := s.scan(context.Background(), func(task *domain.Task) bool {
+			return s.Expired(task, now)
+		})
+		if err == nil && len(expired) > 0 {
+			err = s.Archive(expired, now)
+		}
+		if err != nil {
+			s.logger.Error("Failed to archive expired tasks", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			continue
+		}
+
+		deletedCount := 0
+		for _, task := range expired {
+			mu := s.lock(task.ID)
+			mu.Lock()
+			current, err := s.read(task.ID)
+			if err == nil && current != nil && current.UpdatedAt.Equal(task.UpdatedAt) && current.Status == task.Status {
+				err = s.store.Delete(task.ID)
+				if err == nil {
+					s.track(task.ID, current, nil)
+					s.Evicted(current)
+					deletedCount++
+				}
+			}
+			mu.Unlock()
+			if err != nil {
+				s.logError("Failed to clean up expired task", task.ID, err)
+			}
+		}
+		s.Checked(now)
+
+		if deletedCount > 0 {
+			s.logger.Debug("Cleaned up expired tasks",
+				slog.Attr{Key: "deleted_count", Value: slog.IntValue(deletedCount)},
+			)
+		}
+	}
+}
+
+// RunMerge периодически сжимает файлы данных KV
+func (s *DiskStorage) RunMerge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.store.Merge(); err != nil {
+				s.logger.Error("Failed to merge KV data files", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}
+	}
+}
+
+func (s *DiskStorage) Close() error {
+	close(s.done)
+	return s.store.Close()
+}
+
+// scan возвращает задачи, удовлетворяющие match
+func (s *DiskStorage) scan(ctx context.Context, match func(task *domain.Task) bool) ([]domain.Task, error) {
+	var result []domain.Task
+	err := s.store.ForEach(func(key string, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var task domain.Task
+		if err := json.Unmarshal(value, &task); err != nil {
+			return err
+		}
+		if match(&task) {
+			result = append(result, task)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *DiskStorage) GetAllFilterStatus(ctx context.Context, status domain.TaskStatus) ([]domain.Task, error) {
+	s.logger.Debug("Getting all tasks with status filter",
+		slog.Attr{Key: "status", Value: slog.StringValue(string(status))},
+	)
+
+	result, err := s.scan(ctx, func(task *domain.Task) bool {
+		return status == "" || task.Status == status
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClaimPrecedes(result[j])
+	})
+	s.logger.Debug("Successfully retrieved filtered tasks",
+		slog.Attr{Key: "count", Value: slog.IntValue(len(result))},
+	)
+	return result, nil
+}
+
+func (s *DiskStorage) ListTasks(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error) {
+	s.logger.Debug("Listing tasks",
+		slog.Attr{Key: "sort", Value: slog.StringValue(query.Sort.String())},
+		slog.Attr{Key: "limit", Value: slog.IntValue(query.Limit)},
+	)
+
+	pager := domain.NewTaskPager(query)
+	_, err := s.scan(ctx, func(task *domain.Task) bool {
+		if query.Match(task) {
+			pager.Add(*task)
+		}
+		return false
+	})
+	if err != nil {
+		return domain.TaskPage{}, err
+	}
+	return pager.Page(), nil
+}
+
+func (s *DiskStorage) Get(key string) (domain.Task, bool) {
+	s.logger.Debug("Getting task by key",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	task, err := s.read(key)
+	if err != nil {
+		s.logError("Failed to read task", key, err)
+		return domain.Task{}, false
+	}
+	if task == nil {
+		s.logger.Debug("Task not found",
+			slog.Attr{Key: "key", Value: slog.StringValue(key)},
+		)
+		return domain.Task{}, false
+	}
+	return *task, true
+}
+
+func (s *DiskStorage) SetUpdate(key string, data domain.Task) error {
+	s.logger.Debug("Setting/updating task",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+		slog.Attr{Key: "status", Value: slog.StringValue(string(data.Status))},
+	)
+
+	mu := s.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	prev, err := s.read(key)
+	if err == nil {
+		err = s.write(key, prev, &data)
+	}
+	if err != nil {
+		s.logError("Failed to save task", key, err)
+	}
+	return err
+}
+
+func (s *DiskStorage) UpdateStatus(key string, status domain.TaskStatus) error {
+	s.logger.Debug("Updating task status",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+		slog.Attr{Key: "new_status", Value: slog.StringValue(string(status))},
+	)
+
+	_, err := s.Update(key, func(task *domain.Task) error {
+		task.Status = status
+		task.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		s.logError("Failed to update task status", key, err)
+	}
+	return err
+}
+
+func (s *DiskStorage) Update(key string, fn func(task *domain.Task) error) (domain.Task, error) {
+	s.logger.Debug("Updating task",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	mu := s.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, err := s.read(key)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if current == nil {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+
+	updated := *current
+	if err := fn(&updated); err != nil {
+		return domain.Task{}, err
+	}
+	if err := s.write(key, current, &updated); err != nil {
+		return domain.Task{}, err
+	}
+	return updated, nil
+}
+
+func (s *DiskStorage) Delete(key string, fn func(task *domain.Task) error) (domain.Task, error) {
+	s.logger.Debug("Deleting task",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	mu := s.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, err := s.read(key)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if current == nil {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+
+	deleted := *current
+	if err := fn(&deleted); err != nil {
+		return domain.Task{}, err
+	}
+	if err := s.store.Delete(key); err != nil {
+		return domain.Task{}, err
+	}
+	s.track(key, current, nil)
+	return *current, nil
+}
+
+func (s *DiskStorage) CompareAndSwap(key string, expected int64, fn func(task *domain.Task) error) (domain.Task, error) {
+	return s.Update(key, func(task *domain.Task) error {
+		if err := task.CheckVersion(expected); err != nil {
+			return err
+		}
+		return fn(task)
+	})
+}
+
+func (s *DiskStorage) ClaimNext(ctx context.Context, queue string, workerID string, lease time.Duration) (domain.Task, error) {
+	s.logger.Debug("Claiming next task",
+		slog.Attr{Key: "queue", Value: slog.StringValue(queue)},
+		slog.Attr{Key: "worker_id", Value: slog.StringValue(workerID)},
+	)
+	return s.readiness.Claim(ctx, s.Update, queue, workerID, lease)
+}
+
+func (s *DiskStorage) GetDependents(ctx context.Context, key string) ([]domain.Task, error) {
+	s.logger.Debug("Getting dependent tasks",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+	return s.related(ctx, s.relations.Dependents(key), func(task *domain.Task) bool {
+		return task.DependsOnTask(key)
+	})
+}
+
+func (s *DiskStorage) GetChildren(ctx context.Context, key string) ([]domain.Task, error) {
+	s.logger.Debug("Getting child tasks",
+		slog.Attr{Key: "key", Value: slog.StringValue(key)},
+	)
+
+	result, err := s.related(ctx, s.relations.Children(key), func(task *domain.Task) bool {
+		return task.ParentTaskID == key
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// related читает задачи с ключами из индекса связей. Задача могла измениться
+// после выборки ключей, поэтому связь проверяется повторно через match
+func (s *DiskStorage) related(ctx context.Context, keys []string, match func(task *domain.Task) bool) ([]domain.Task, error) {
+	result := make([]domain.Task, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		task, err := s.read(key)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil && match(task) {
+			result = append(result, *task)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package task_repo
+
+import (
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/archive"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/kv"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"testing"
+)
+
+func TestDiskStorageContract(t *testing.T) {
+	contract.RunTaskRepository(t, func(t *testing.T, dir string) contract.TaskStorage {
+		t.Helper()
+		store, err := kv.Open(dir, kv.Options{Sync: kv.SyncAlways}, contract.NopLogger{})
+		if err != nil {
+			t.Fatalf("kv.Open: %v", err)
+		}
+		keeper := retention.NewKeeper(domain.RetentionPolicy{}, 0, archive.Nop{})
+		storage, err := NewDiskStorage(store, 4, keeper, 0, 0, contract.NopLogger{})
+		if err != nil {
+			store.Close()
+			t.Fatalf("NewDiskStorage: %v", err)
+		}
+		return storage
+	})
+}
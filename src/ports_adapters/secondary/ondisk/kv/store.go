@@ -0,0 +1,529 @@
+package kv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+type SyncPolicy string
+
+const (
+	SyncAlways   SyncPolicy = "always"
+	SyncInterval SyncPolicy = "interval"
+	SyncNever    SyncPolicy = "never"
+)
+
+const (
+	dataFilePattern = "%09d.data"
+	headerSize      = 12
+	tombstone       = ^uint32(0)
+	maxRecordLen    = 64 << 20
+)
+
+var (
+	ErrCorrupted = errors.New("kv record corrupted")
+	ErrFailed    = errors.New("kv store failed")
+)
+
+type Options struct {
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// MaxFileSize размер файла данных, после которого запись продолжается в новый файл
+	MaxFileSize int64
+}
+
+type entry struct {
+	fileID    uint32
+	offset    int64
+	valueSize uint32
+	size      int64
+}
+
+// Store встроенное хранилище ключ-значение в стиле bitcask: записи только
+// дописываются в конец файлов данных, а в памяти хранится лишь индекс ключей
+// с позициями последних значений. Каждая запись имеет вид
+// [crc32 uint32][длина ключа uint32][длина значения uint32][ключ][значение]
+type Store struct {
+	mu         sync.RWMutex
+	logger     domain.ILogger
+	dir        string
+	opts       Options
+	keydir     map[string]entry
+	readers    map[uint32]*os.File
+	active     *os.File
+	activeID   uint32
+	activeSize int64
+	stale      int64
+	dirty      bool
+	// failed ошибка, после которой активный файл не удалось вернуть к последней
+	// целой записи; дальнейшая запись в хранилище отклоняется
+	failed error
+	done   chan struct{}
+}
+
+func Open(dir string, opts Options, logger domain.ILogger) (*Store, error) {
+	switch opts.Sync {
+	case SyncAlways, SyncInterval, SyncNever:
+	default:
+		return nil, fmt.Errorf("invalid kv sync policy: %s, must be one of: always, interval, never", opts.Sync)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		logger:  logger,
+		dir:     dir,
+		opts:    opts,
+		keydir:  make(map[string]entry),
+		readers: make(map[uint32]*os.File),
+		done:    make(chan struct{}),
+	}
+
+	ids, err := s.fileIDs()
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		if err := s.load(id, i == len(ids)-1); err != nil {
+			s.closeFiles()
+			return nil, err
+		}
+	}
+
+	activeID := uint32(1)
+	if len(ids) > 0 {
+		activeID = ids[len(ids)-1]
+	}
+	if err := s.openActive(activeID); err != nil {
+		s.closeFiles()
+		return nil, err
+	}
+	if opts.Sync == SyncInterval && opts.SyncInterval > 0 {
+		go s.syncLoop()
+	}
+	return s, nil
+}
+
+func (s *Store) path(id uint32) string {
+	return filepath.Join(s.dir, fmt.Sprintf(dataFilePattern, id))
+}
+
+func (s *Store) fileIDs() ([]uint32, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint32
+	for _, e := range entries {
+		var id uint32
+		if _, err := fmt.Sscanf(e.Name(), dataFilePattern, &id); err != nil {
+			continue
+		}
+		if e.Name() != fmt.Sprintf(dataFilePattern, id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// load восстанавливает индекс ключей по файлу данных. Поврежденный хвост последнего
+// файла (например, после аварийной остановки во время записи) отбрасывается
+func (s *Store) load(id uint32, last bool) error {
+	path := s.path(id)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	s.readers[id] = file
+
+	reader := bufio.NewReader(io.NewSectionReader(file, 0, 1<<62))
+	var offset int64
+	for {
+		key, valueSize, size, err := readRecord(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("data file %s at offset %d: %w", path, offset, err)
+			}
+			s.logger.Error("KV data file is corrupted, truncating tail",
+				slog.Attr{Key: "file", Value: slog.StringValue(path)},
+				slog.Attr{Key: "offset", Value: slog.Int64Value(offset)},
+				slog.Attr{Key: "error", Value: slog.StringValue(err.Error())},
+			)
+			return os.Truncate(path, offset)
+		}
+		if prev, ok := s.keydir[key]; ok {
+			s.stale += prev.size
+		}
+		if valueSize == tombstone {
+			s.stale += size
+			delete(s.keydir, key)
+		} else {
+			s.keydir[key] = entry{fileID: id, offset: offset, valueSize: valueSize, size: size}
+		}
+		offset += size
+	}
+}
+
+func readRecord(reader io.Reader) (string, uint32, int64, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		if err == io.EOF {
+			return "", 0, 0, io.EOF
+		}
+		return "", 0, 0, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	checksum := binary.LittleEndian.Uint32(header[0:4])
+	keySize := binary.LittleEndian.Uint32(header[4:8])
+	valueSize := binary.LittleEndian.Uint32(header[8:12])
+
+	bodySize := keySize
+	if valueSize != tombstone {
+		bodySize += valueSize
+	}
+	if bodySize > maxRecordLen {
+		return "", 0, 0, fmt.Errorf("%w: invalid record length %d", ErrCorrupted, bodySize)
+	}
+	body := make([]byte, bodySize)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return "", 0, 0, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(body)
+	if crc.Sum32() != checksum {
+		return "", 0, 0, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+	return string(body[:keySize]), valueSize, int64(headerSize) + int64(bodySize), nil
+}
+
+func encodeRecord(key string, value []byte, deleted bool) []byte {
+	valueSize := uint32(len(value))
+	if deleted {
+		valueSize = tombstone
+		value = nil
+	}
+	buf := make([]byte, headerSize+len(key)+len(value))
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(key)))
+	binary.LittleEndian.PutUint32(buf[8:12], valueSize)
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], value)
+	binary.LittleEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[4:]))
+	return buf
+}
+
+func (s *Store) openActive(id uint32) error {
+	file, err := os.OpenFile(s.path(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, ok := s.readers[id]; !ok {
+		reader, err := os.Open(s.path(id))
+		if err != nil {
+			file.Close()
+			return err
+		}
+		s.readers[id] = reader
+	}
+	s.active = file
+	s.activeID = id
+	s.activeSize = info.Size()
+	return nil
+}
+
+// rotateLocked закрывает активный файл данных и начинает новый
+func (s *Store) rotateLocked() error {
+	if err := s.active.Sync(); err != nil {
+		return err
+	}
+	s.dirty = false
+	if err := s.active.Close(); err != nil {
+		return err
+	}
+	return s.openActive(s.activeID + 1)
+}
+
+// appendLocked дописывает запись в активный файл и возвращает ее позицию. Если запись
+// или fsync не удались, файл обрезается до последней целой записи: вызывающий не
+// обновляет индекс ключей, поэтому запись не должна появиться при следующем открытии,
+// а позиции следующих записей должны совпадать с activeSize
+func (s *Store) appendLocked(key string, value []byte, deleted bool) (entry, error) {
+	if s.failed != nil {
+		return entry{}, s.failed
+	}
+	if s.opts.MaxFileSize > 0 && s.activeSize >= s.opts.MaxFileSize {
+		if err := s.rotateLocked(); err != nil {
+			return entry{}, err
+		}
+	}
+
+	record := encodeRecord(key, value, deleted)
+	_, err := s.active.Write(record)
+	if err == nil && s.opts.Sync == SyncAlways {
+		err = s.active.Sync()
+	}
+	if err != nil {
+		if truncErr := s.active.Truncate(s.activeSize); truncErr != nil {
+			s.failed = fmt.Errorf("%w: %v", ErrFailed, errors.Join(err, truncErr))
+			return entry{}, s.failed
+		}
+		return entry{}, err
+	}
+
+	e := entry{fileID: s.activeID, offset: s.activeSize, valueSize: uint32(len(value)), size: int64(len(record))}
+	s.activeSize += int64(len(record))
+	if s.opts.Sync != SyncAlways {
+		s.dirty = true
+	}
+	return e, nil
+}
+
+func (s *Store) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.appendLocked(key, value, false)
+	if err != nil {
+		return err
+	}
+	if prev, ok := s.keydir[key]; ok {
+		s.stale += prev.size
+	}
+	s.keydir[key] = e
+	return nil
+}
+
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.keydir[key]
+	if !ok {
+		return nil
+	}
+	e, err := s.appendLocked(key, nil, true)
+	if err != nil {
+		return err
+	}
+	s.stale += prev.size + e.size
+	delete(s.keydir, key)
+	return nil
+}
+
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.keydir[key]
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := s.readLocked(key, e)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// readLocked читает запись целиком и проверяет контрольную сумму, как при загрузке:
+// повреждение файла данных после открытия не должно вернуть испорченное значение
+func (s *Store) readLocked(key string, e entry) ([]byte, error) {
+	record := make([]byte, e.size)
+	if _, err := s.readers[e.fileID].ReadAt(record, e.offset); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(record[4:]) != binary.LittleEndian.Uint32(record[0:4]) {
+		return nil, fmt.Errorf("%w: checksum mismatch for key %s", ErrCorrupted, key)
+	}
+	if string(record[headerSize:headerSize+len(key)]) != key {
+		return nil, fmt.Errorf("%w: record at offset %d does not belong to key %s", ErrCorrupted, e.offset, key)
+	}
+	return record[headerSize+len(key):], nil
+}
+
+// Keys возвращает все ключи хранилища
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.keydir))
+	for key := range s.keydir {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ForEach вызывает fn для каждой пары ключ-значение. Ключи, удаленные во время
+// обхода, пропускаются
+func (s *Store) ForEach(fn func(key string, value []byte) error) error {
+	for _, key := range s.Keys() {
+		value, ok, err := s.Get(key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge переписывает актуальные значения в новые файлы данных и удаляет старые,
+// освобождая место, занятое перезаписанными и удаленными значениями. На время
+// слияния запись в хранилище блокируется. Если перезаписанных значений нет, слияние не выполняется
+func (s *Store) Merge() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stale == 0 {
+		return nil
+	}
+	reclaimed := s.stale
+
+	oldIDs := make([]uint32, 0, len(s.readers))
+	for id := range s.readers {
+		oldIDs = append(oldIDs, id)
+	}
+	if err := s.rotateLocked(); err != nil {
+		return err
+	}
+
+	for key, e := range s.keydir {
+		value, err := s.readLocked(key, e)
+		if err != nil {
+			return err
+		}
+		merged, err := s.appendLocked(key, value, false)
+		if err != nil {
+			return err
+		}
+		s.keydir[key] = merged
+	}
+	if err := s.active.Sync(); err != nil {
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+	s.dirty = false
+	s.stale = 0
+
+	// Старые файлы удаляются от ранних к поздним: если процесс остановится
+	// посередине, удаление ключа в более позднем файле не пропадет раньше,
+	// чем файл с его значением
+	sort.Slice(oldIDs, func(i, j int) bool { return oldIDs[i] < oldIDs[j] })
+	for _, id := range oldIDs {
+		s.readers[id].Close()
+		delete(s.readers, id)
+		if err := os.Remove(s.path(id)); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("KV data files merged",
+		slog.Attr{Key: "keys", Value: slog.IntValue(len(s.keydir))},
+		slog.Attr{Key: "removedFiles", Value: slog.IntValue(len(oldIDs))},
+		slog.Attr{Key: "reclaimedBytes", Value: slog.Int64Value(reclaimed)},
+	)
+	return nil
+}
+
+// syncDir сбрасывает на диск каталог, чтобы созданные в нем файлы пережили сбой ОС
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+// RunMerge периодически сжимает файлы данных, пока хранилище не закрыто
+func (s *Store) RunMerge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.Merge(); err != nil {
+				s.logger.Error("Failed to merge KV data files", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}
+	}
+}
+
+func (s *Store) syncLoop() {
+	ticker := time.NewTicker(s.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.sync(); err != nil {
+				s.logger.Error("Failed to fsync KV data file", slog.Attr{Key: "error", Value: slog.StringValue(err.Error())})
+			}
+		}
+	}
+}
+
+func (s *Store) sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+	s.dirty = false
+	return s.active.Sync()
+}
+
+func (s *Store) closeFiles() {
+	for _, reader := range s.readers {
+		reader.Close()
+	}
+	if s.active != nil {
+		s.active.Close()
+	}
+}
+
+func (s *Store) Close() error {
+	close(s.done)
+	if err := s.sync(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeFiles()
+	return nil
+}
@@ -0,0 +1,120 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"svc-task_master/src/ports_adapters/secondary/contract"
+	"testing"
+)
+
+func openStore(t *testing.T, dir string, opts Options) *Store {
+	t.Helper()
+	store, err := Open(dir, opts, contract.NopLogger{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return store
+}
+
+func TestMergeKeepsDeletedKeysDeleted(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Sync: SyncAlways, MaxFileSize: 64}
+	store := openStore(t, dir, opts)
+
+	for i := 0; i < 10; i++ {
+		if err := store.Put(fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("value-%d", i))); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	for i := 0; i < 10; i += 2 {
+		if err := store.Delete(fmt.Sprintf("key-%d", i)); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	if err := store.Merge(); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	store = openStore(t, dir, opts)
+	defer store.Close()
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		value, ok, err := store.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", key, err)
+		}
+		if deleted := i%2 == 0; ok == deleted {
+			t.Fatalf("Get(%s) found = %v after merge and reopen", key, ok)
+		}
+		if ok && string(value) != fmt.Sprintf("value-%d", i) {
+			t.Fatalf("Get(%s) = %q", key, value)
+		}
+	}
+}
+
+func TestGetVerifiesChecksum(t *testing.T) {
+	store := openStore(t, t.TempDir(), Options{Sync: SyncAlways})
+	defer store.Close()
+
+	if err := store.Put("key", []byte("value")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	file, err := os.OpenFile(store.path(store.activeID), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open data file: %v", err)
+	}
+	if _, err := file.WriteAt([]byte("X"), int64(headerSize+len("key"))); err != nil {
+		t.Fatalf("corrupt data file: %v", err)
+	}
+	file.Close()
+
+	if _, _, err := store.Get("key"); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Get of a corrupted value: %v, want ErrCorrupted", err)
+	}
+}
+
+func TestFailedAppendDoesNotShiftOffsets(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, Options{Sync: SyncAlways})
+	if err := store.Put("a", []byte("first")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// В дескриптор только для чтения нельзя ни дописать запись, ни обрезать файл:
+	// хранилище должно перестать принимать записи, а не сдвинуть позиции
+	active := store.active
+	readOnly, err := os.Open(store.path(store.activeID))
+	if err != nil {
+		t.Fatalf("open data file: %v", err)
+	}
+	store.active = readOnly
+	if err := store.Put("b", []byte("second")); !errors.Is(err, ErrFailed) {
+		t.Fatalf("Put to a read-only file: %v, want ErrFailed", err)
+	}
+	if err := store.Delete("a"); !errors.Is(err, ErrFailed) {
+		t.Fatalf("Delete after failure: %v, want ErrFailed", err)
+	}
+	store.active = active
+	readOnly.Close()
+
+	value, ok, err := store.Get("a")
+	if err != nil || !ok || string(value) != "first" {
+		t.Fatalf("Get(a) = %q, %v, %v", value, ok, err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	store = openStore(t, dir, Options{Sync: SyncAlways})
+	defer store.Close()
+	if _, ok, _ := store.Get("b"); ok {
+		t.Fatal("failed Put is visible after reopen")
+	}
+	if value, ok, err := store.Get("a"); err != nil || !ok || string(value) != "first" {
+		t.Fatalf("Get(a) after reopen = %q, %v, %v", value, ok, err)
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"svc-task_master/src/common/config"
+	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/archive"
+	"svc-task_master/src/ports_adapters/secondary/audit"
+	"svc-task_master/src/ports_adapters/secondary/inmemory/db/idempotency_repo"
+	"svc-task_master/src/ports_adapters/secondary/inmemory/db/schedule_repo"
+	"svc-task_master/src/ports_adapters/secondary/ondisk/kv"
+	"svc-task_master/src/ports_adapters/secondary/retention"
+	"time"
+)
+
+type Repository struct {
+	InMemoryDB    domain.IInMemoRepository
+	RetentionDB   domain.IRetentionRepository
+	ArchiveDB     domain.ITaskArchive
+	ScheduleDB    domain.IScheduleRepository
+	IdempotencyDB domain.IIdempotencyRepository
+	AuditDB       domain.IAuditRepository
+	closers       []io.Closer
+}
+
+// TaskStorage хранилище задач, из которого собирается репозиторий
+type TaskStorage interface {
+	domain.IInMemoRepository
+	domain.IRetentionRepository
+	io.Closer
+}
+
+// StateStore параметры хранилищ ключ-значение, в которых вместе с задачами сохраняются
+// расписания и ключи идемпотентности. Пустой Dir — они хранятся только в памяти
+type StateStore struct {
+	Dir           string
+	Options       kv.Options
+	MergeInterval time.Duration
+}
+
+// Compose собирает репозиторий из хранилища задач любого адаптера, архива,
+// журнала аудита, хранилищ расписаний и ключей идемпотентности
+func Compose(logger domain.ILogger, cfg *config.Config, tasks TaskStorage, archive domain.ITaskArchive, state StateStore) (*Repository, error) {
+	auditLog, err := audit.NewLog(cfg.Audit.Dir, logger)
+	if err != nil {
+		return nil, err
+	}
+	repo := &Repository{
+		InMemoryDB:  tasks,
+		RetentionDB: tasks,
+		ArchiveDB:   archive,
+		AuditDB:     auditLog,
+		closers:     []io.Closer{auditLog},
+	}
+	if err := repo.openState(logger, cfg, state); err != nil {
+		// Хранилище задач при ошибке закрывает вызывающий
+		repo.Close()
+		return nil, err
+	}
+	repo.closers = append([]io.Closer{tasks}, repo.closers...)
+	return repo, nil
+}
+
+// openState создает хранилища расписаний и ключей идемпотентности
+func (r *Repository) openState(logger domain.ILogger, cfg *config.Config, state StateStore) error {
+	if state.Dir == "" {
+		idempotency := idempotency_repo.NewIdempotencyStorage(cfg.Idempotency.TTL, logger)
+		r.ScheduleDB = schedule_repo.NewScheduleStorage(logger)
+		r.IdempotencyDB = idempotency
+		r.closers = append(r.closers, idempotency)
+		return nil
+	}
+
+	scheduleStore, err := r.openStateStore(filepath.Join(state.Dir, "schedules"), state, logger)
+	if err != nil {
+		return err
+	}
+	if r.ScheduleDB, err = schedule_repo.OpenScheduleStorage(scheduleStore, logger); err != nil {
+		return err
+	}
+
+	idempotencyStore, err := r.openStateStore(filepath.Join(state.Dir, "idempotency"), state, logger)
+	if err != nil {
+		return err
+	}
+	idempotency, err := idempotency_repo.OpenIdempotencyStorage(idempotencyStore, cfg.Idempotency.TTL, logger)
+	if err != nil {
+		return err
+	}
+	r.IdempotencyDB = idempotency
+	r.closers = append(r.closers, idempotency)
+	return nil
+}
+
+func (r *Repository) openStateStore(dir string, state StateStore, logger domain.ILogger) (*kv.Store, error) {
+	store, err := kv.Open(dir, state.Options, logger)
+	if err != nil {
+		return nil, err
+	}
+	r.closers = append(r.closers, store)
+	if state.MergeInterval > 0 {
+		go store.RunMerge(state.MergeInterval)
+	}
+	return store, nil
+}
+
+// NewRetention создает правила хранения задач и архив удаляемых задач из конфигурации
+func NewRetention(logger domain.ILogger, cfg *config.Config) (*retention.Keeper, domain.ITaskArchive, error) {
+	policy, err := domain.ParseRetentionPolicy(cfg.Retention.Rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	deadLetterTTL, err := domain.ParseRetentionTTL(cfg.Retention.DeadLetterTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var taskArchive domain.ITaskArchive = archive.Nop{}
+	if cfg.Archive.Dir == config.ArchiveDisabled {
+		logger.Warn("Task archive is disabled, expired tasks are deleted permanently")
+	} else {
+		taskArchive, err = archive.NewFileArchive(cfg.Archive.Dir, cfg.Archive.RetentionDays, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return retention.NewKeeper(policy, deadLetterTTL, taskArchive), taskArchive, nil
+}
+
+// Close закрывает хранилища в порядке, обратном открытию: фоновые задачи
+// хранилищ останавливаются раньше, чем закрываются файлы, в которые они пишут
+func (r *Repository) Close() error {
+	var errs []error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package retention
+
+import (
+	"svc-task_master/src/domain"
+	"sync"
+	"time"
+)
+
+// Keeper хранит правила хранения задач и статистику удалений и передает
+// удаляемые задачи в архив; используется адаптерами хранилища задач
+type Keeper struct {
+	mu            sync.RWMutex
+	archive       domain.ITaskArchive
+	policy        domain.RetentionPolicy
+	deadLetterTTL domain.RetentionTTL
+	total         int64
+	byStatus      map[domain.TaskStatus]int64
+	byQueue       map[string]int64
+	lastRunAt     *time.Time
+}
+
+func NewKeeper(policy domain.RetentionPolicy, deadLetterTTL domain.RetentionTTL, archive domain.ITaskArchive) *Keeper {
+	return &Keeper{
+		archive:       archive,
+		policy:        policy,
+		deadLetterTTL: deadLetterTTL,
+		byStatus:      make(map[domain.TaskStatus]int64),
+		byQueue:       make(map[string]int64),
+	}
+}
+
+func (k *Keeper) GetRetentionPolicy() domain.RetentionPolicy {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	rules := make([]domain.RetentionRule, len(k.policy.Rules))
+	copy(rules, k.policy.Rules)
+	return domain.RetentionPolicy{Rules: rules}
+}
+
+func (k *Keeper) SetRetentionPolicy(policy domain.RetentionPolicy) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.policy = policy
+}
+
+func (k *Keeper) GetDeadLetterTTL() domain.RetentionTTL {
+	return k.deadLetterTTL
+}
+
+func (k *Keeper) GetEvictionStats() domain.EvictionStats {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	stats := domain.EvictionStats{
+		Total:     k.total,
+		ByStatus:  make(map[domain.TaskStatus]int64, len(k.byStatus)),
+		ByQueue:   make(map[string]int64, len(k.byQueue)),
+		LastRunAt: k.lastRunAt,
+	}
+	for status, count := range k.byStatus {
+		stats.ByStatus[status] = count
+	}
+	for queue, count := range k.byQueue {
+		stats.ByQueue[queue] = count
+	}
+	return stats
+}
+
+// Expired сообщает, что задачу пора удалить по текущим правилам; задачи из DLQ
+// удаляются по истечении deadLetterTTL
+func (k *Keeper) Expired(task *domain.Task, now time.Time) bool {
+	if task.DeadLetter != nil {
+		return task.DeadLetterExpired(k.deadLetterTTL, now)
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.policy.Expired(task, now)
+}
+
+// Archive сохраняет задачи, отобранные для удаления, в архив. Хранилище удаляет
+// задачи только после успешной архивации
+func (k *Keeper) Archive(tasks []domain.Task, now time.Time) error {
+	return k.archive.Archive(tasks, now)
+}
+
+// Evicted учитывает удаленную задачу в статистике
+func (k *Keeper) Evicted(task *domain.Task) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.total++
+	k.byStatus[task.Status]++
+	k.byQueue[task.Queue]++
+}
+
+// Checked отмечает время завершения очередной проверки
+func (k *Keeper) Checked(now time.Time) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.lastRunAt = &now
+}
@@ -4,18 +4,57 @@ import (
 	"svc-task_master/src/application"
 	"svc-task_master/src/application/commands"
 	"svc-task_master/src/application/queries"
+	"svc-task_master/src/common/config"
+	"svc-task_master/src/common/decorator"
 	"svc-task_master/src/domain"
+	"svc-task_master/src/ports_adapters/secondary/repository"
 )
 
-func InitApp(repo domain.IInMemoRepository, logger domain.ILogger) application.App {
+func InitApp(repo *repository.Repository, logger domain.ILogger, cfg *config.Config) application.App {
+	lifecycle := commands.NewTaskLifecycle(logger, repo.InMemoryDB, repo.ArchiveDB)
+	createTask := audited("CreateTask", commands.NewCreateTaskCommnad(logger, repo.InMemoryDB, lifecycle, repo.IdempotencyDB), repo, logger)
 	return application.App{
 		Command: application.Commands{
-			CreateTask: commands.NewCreateTaskCommnad(logger, repo),
-			UpdateTask: commands.NewUpdateTaskCommnad(logger, repo),
+			CreateTask:           createTask,
+			UpdateTask:           audited("UpdateTask", commands.NewUpdateTaskCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			ClaimTask:            audited("ClaimTask", commands.NewClaimTaskCommnad(logger, repo.InMemoryDB, lifecycle, cfg.Worker.LeaseTTL), repo, logger),
+			HeartbeatTask:        commands.NewHeartbeatTaskCommnad(logger, repo.InMemoryDB, cfg.Worker.LeaseTTL),
+			ReleaseExpiredLeases: audited("ReleaseExpiredLeases", commands.NewReleaseExpiredLeasesCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			CreateSchedule:       audited("CreateSchedule", commands.NewCreateScheduleCommnad(logger, repo.ScheduleDB), repo, logger),
+			DeleteSchedule:       audited("DeleteSchedule", commands.NewDeleteScheduleCommnad(logger, repo.ScheduleDB), repo, logger),
+			FireSchedules:        audited("FireSchedules", commands.NewFireSchedulesCommnad(logger, repo.ScheduleDB, createTask, cfg.Schedule.MisfireThreshold), repo, logger),
+			UpdateRetention:      audited("UpdateRetention", commands.NewUpdateRetentionCommnad(logger, repo.RetentionDB), repo, logger),
+			CancelTask:           audited("CancelTask", commands.NewCancelTaskCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			CompleteTask:         audited("CompleteTask", commands.NewCompleteTaskCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			FailTask:             audited("FailTask", commands.NewFailTaskCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			RequeueDeadLetter:    audited("RequeueDeadLetter", commands.NewRequeueDeadLetterCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			RequeueDeadLetters:   audited("RequeueDeadLetters", commands.NewRequeueDeadLettersCommnad(logger, repo.InMemoryDB, lifecycle), repo, logger),
+			PurgeDeadLetter:      audited("PurgeDeadLetter", commands.NewPurgeDeadLetterCommnad(logger, repo.InMemoryDB), repo, logger),
+			PurgeDeadLetters:     audited("PurgeDeadLetters", commands.NewPurgeDeadLettersCommnad(logger, repo.InMemoryDB), repo, logger),
 		},
 		Query: application.Queries{
-			GetTasks: queries.NewGetTasksQuery(logger, repo),
-			GetTask:  queries.NewGetTaskIdQuery(logger, repo),
+			GetTasks:         queries.NewGetTasksQuery(logger, repo.InMemoryDB),
+			GetTask:          queries.NewGetTaskIdQuery(logger, repo.InMemoryDB),
+			GetSchedules:     queries.NewGetSchedulesQuery(logger, repo.ScheduleDB),
+			GetTaskChildren:  queries.NewGetTaskChildrenQuery(logger, repo.InMemoryDB),
+			GetTaskTree:      queries.NewGetTaskTreeQuery(logger, repo.InMemoryDB),
+			GetTaskAttempts:  queries.NewGetTaskAttemptsQuery(logger, repo.InMemoryDB),
+			GetRetention:     queries.NewGetRetentionQuery(logger, repo.RetentionDB),
+			GetArchivedTask:  queries.NewGetArchivedTaskQuery(logger, repo.ArchiveDB),
+			GetArchivedTasks: queries.NewGetArchivedTasksQuery(logger, repo.ArchiveDB),
+			SearchTasks:      queries.NewSearchTasksQuery(logger, repo.InMemoryDB),
+			GetTaskAudit:     queries.NewGetTaskAuditQuery(logger, repo.AuditDB),
+			GetAudit:         queries.NewGetAuditQuery(logger, repo.AuditDB),
+			GetDeadLetters:   queries.NewGetDeadLettersQuery(logger, repo.InMemoryDB),
+			GetDeadLetter:    queries.NewGetDeadLetterQuery(logger, repo.InMemoryDB),
 		},
 	}
 }
+
+// audited подключает к команде журнал аудита. Фоновые команды (истечение аренд,
+// запуск расписаний) выполняются от имени system и записывают только измененные задачи:
+// задачи по расписаниям записываются как CreateTask. Heartbeat не записывается: он только
+// продлевает аренду, а запись на каждый heartbeat сбрасывала бы журнал на диск чаще всех команд
+func audited[C any, R any](name string, handler decorator.CommandHandlerDecorator[C, R], repo *repository.Repository, logger domain.ILogger) decorator.CommandHandlerDecorator[C, R] {
+	return decorator.ApplyCommandAuditDecorator(handler, name, repo.AuditDB, repo.InMemoryDB, logger)
+}